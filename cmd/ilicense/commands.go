@@ -0,0 +1,475 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+// options holds flags shared by all commands.
+type options struct {
+	json           bool
+	publicKey      string
+	publicKeyFile  string
+	storagePath    string
+	productCode    string
+	productVersion string
+}
+
+func newFlagSet(e *env, name string, o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("ilicense "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.BoolVar(&o.json, "json", false, "print JSON output")
+	fs.StringVar(&o.publicKey, "public-key", "", "RSA public key (PEM or base64 DER); defaults to $ILICENSE_PUBLIC_KEY")
+	fs.StringVar(&o.publicKeyFile, "public-key-file", "", "file holding the RSA public key")
+	fs.StringVar(&o.storagePath, "storage", ilicense.DefaultConfig().StoragePath, "license storage path")
+	fs.StringVar(&o.productCode, "product-code", "", "expected product code")
+	fs.StringVar(&o.productVersion, "product-version", "", "running product version")
+	return fs
+}
+
+func (o *options) key() (string, error) {
+	switch {
+	case o.publicKey != "":
+		return o.publicKey, nil
+	case o.publicKeyFile != "":
+		b, err := os.ReadFile(o.publicKeyFile)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return os.Getenv("ILICENSE_PUBLIC_KEY"), nil
+	}
+}
+
+func (o *options) client(requireKey bool) (*ilicense.Client, error) {
+	key, err := o.key()
+	if err != nil {
+		return nil, err
+	}
+	if requireKey && strings.TrimSpace(key) == "" {
+		return nil, errors.New("public key required: set -public-key, -public-key-file or ILICENSE_PUBLIC_KEY")
+	}
+	cfg := ilicense.DefaultConfig()
+	cfg.PublicKey = key
+	cfg.StoragePath = o.storagePath
+	cfg.ProductCode = o.productCode
+	cfg.ProductVersion = o.productVersion
+	return ilicense.NewClient(&cfg), nil
+}
+
+type inspectResult struct {
+	License *ilicense.License `json:"license,omitempty"`
+	// Verified reports whether License came from a code whose signature was
+	// verified; otherwise it is an untrusted decode for diagnostics.
+	Verified bool                       `json:"verified"`
+	Report   *ilicense.ValidationReport `json:"report,omitempty"`
+	Error    string                     `json:"error,omitempty"`
+}
+
+// inspectCode verifies code with client when it is non-nil and falls back to
+// an unverified decode so the payload can still be shown.
+func inspectCode(client *ilicense.Client, code string) inspectResult {
+	var r inspectResult
+	var err error
+	if client != nil {
+		r.License, r.Report, err = client.Inspect(code)
+		r.Verified = r.License != nil
+	}
+	if r.License == nil {
+		license, decodeErr := ilicense.DecodeUnverified(code)
+		if err == nil {
+			err = decodeErr
+		}
+		r.License = license
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+func runInspect(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "inspect", &o)
+	file := fs.String("file", "", "read activation codes from a file such as license.dat")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	codes, err := readCodes(e, fs.Args(), *file)
+	if err != nil {
+		return err
+	}
+	key, err := o.key()
+	if err != nil {
+		return err
+	}
+	var client *ilicense.Client
+	if strings.TrimSpace(key) != "" {
+		if client, err = o.client(true); err != nil {
+			return err
+		}
+	}
+
+	failed := false
+	results := make([]inspectResult, 0, len(codes))
+	for _, code := range codes {
+		r := inspectCode(client, code)
+		failed = failed || r.Error != ""
+		results = append(results, r)
+	}
+	if err := printResults(e.stdout, o.json, results); err != nil {
+		return err
+	}
+	if failed {
+		return errSilent
+	}
+	return nil
+}
+
+func runVerify(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "verify", &o)
+	file := fs.String("file", "", "read activation codes from a file such as license.dat")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	codes, err := readCodes(e, fs.Args(), *file)
+	if err != nil {
+		return err
+	}
+	client, err := o.client(true)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	results := make([]inspectResult, 0, len(codes))
+	for _, code := range codes {
+		var r inspectResult
+		var err error
+		r.License, r.Report, err = client.Inspect(code)
+		r.Verified = r.License != nil
+		if err != nil {
+			r.Error = err.Error()
+			failed = true
+		}
+		results = append(results, r)
+	}
+	if err := printResults(e.stdout, o.json, results); err != nil {
+		return err
+	}
+	if failed {
+		return errSilent
+	}
+	return nil
+}
+
+func runActivate(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "activate", &o)
+	file := fs.String("file", "", "read the activation code from a file")
+	force := fs.Bool("force", false, "apply the code even if it downgrades the stored license")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	codes, err := readCodes(e, fs.Args(), *file)
+	if err != nil {
+		return err
+	}
+	if len(codes) != 1 {
+		return errors.New("activate expects exactly one activation code")
+	}
+	client, err := o.client(true)
+	if err != nil {
+		return err
+	}
+	license, err := client.ActivateWithOptions(codes[0], ilicense.ActivateOptions{Force: *force})
+	if err != nil {
+		return err
+	}
+	if o.json {
+		return writeJSON(e.stdout, license)
+	}
+	fmt.Fprintf(e.stdout, "activated, stored in %s\n", o.storagePath)
+	printLicense(e.stdout, license)
+	return nil
+}
+
+type statusResult struct {
+	Status      ilicense.LicenseStatus `json:"status"`
+	Error       string                 `json:"error,omitempty"`
+	StoragePath string                 `json:"storage_path"`
+	License     *ilicense.License      `json:"license,omitempty"`
+	Licenses    []*ilicense.License    `json:"licenses,omitempty"`
+}
+
+func runStatus(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "status", &o)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("status takes no arguments")
+	}
+	key, err := o.key()
+	if err != nil {
+		return err
+	}
+	cfg := ilicense.DefaultConfig()
+	cfg.PublicKey = key
+	cfg.StoragePath = o.storagePath
+	cfg.ProductCode = o.productCode
+	cfg.ProductVersion = o.productVersion
+	cfg.ValidateOnStartup = true
+	cfg.AllowStartWhenExpired = false
+	client := ilicense.NewClient(&cfg)
+
+	initErr := client.Init()
+	status, statusErr := client.CheckLicenseStatus()
+	r := statusResult{Status: status, StoragePath: o.storagePath, License: client.GetCurrentLicense()}
+	if licenses := client.Licenses(); len(licenses) > 1 {
+		r.Licenses = licenses
+	}
+	switch {
+	case initErr != nil && !errors.Is(initErr, ilicense.ErrLicenseNotFound) && !errors.Is(initErr, ilicense.ErrLicenseExpired):
+		r.Error = initErr.Error()
+	case statusErr != nil:
+		r.Error = statusErr.Error()
+	}
+
+	if o.json {
+		if err := writeJSON(e.stdout, r); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(e.stdout, "status:         %s\n", r.Status)
+		fmt.Fprintf(e.stdout, "storage:        %s\n", r.StoragePath)
+		if r.Error != "" {
+			fmt.Fprintf(e.stdout, "error:          %s\n", r.Error)
+		}
+		if r.License != nil {
+			printLicense(e.stdout, r.License)
+		}
+		for i, l := range r.Licenses {
+			fmt.Fprintf(e.stdout, "\n[license %d]\n", i+1)
+			printLicense(e.stdout, l)
+		}
+	}
+	if status != ilicense.LicenseStatusValid && status != ilicense.LicenseStatusTrial {
+		return errSilent
+	}
+	return nil
+}
+
+func runDeactivate(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "deactivate", &o)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("deactivate takes no arguments")
+	}
+	client, err := o.client(false)
+	if err != nil {
+		return err
+	}
+	if err := client.Deactivate(); err != nil {
+		return err
+	}
+	if o.json {
+		return writeJSON(e.stdout, map[string]any{"deactivated": true, "storage_path": o.storagePath})
+	}
+	fmt.Fprintf(e.stdout, "deactivated, removed %s\n", o.storagePath)
+	return nil
+}
+
+func runFingerprint(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "fingerprint", &o)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("fingerprint takes no arguments")
+	}
+	fingerprint, err := ilicense.Fingerprint()
+	if err != nil {
+		return err
+	}
+	if o.json {
+		return writeJSON(e.stdout, map[string]string{"fingerprint": fingerprint})
+	}
+	fmt.Fprintln(e.stdout, fingerprint)
+	return nil
+}
+
+type requestResult struct {
+	Request *ilicense.ActivationRequest `json:"request"`
+	Encoded string                      `json:"encoded"`
+}
+
+func runRequest(e *env, args []string) error {
+	var o options
+	fs := newFlagSet(e, "request", &o)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("request takes no arguments")
+	}
+	key, err := o.key()
+	if err != nil {
+		return err
+	}
+	cfg := ilicense.DefaultConfig()
+	cfg.PublicKey = key
+	cfg.StoragePath = o.storagePath
+	cfg.ProductCode = o.productCode
+	cfg.ProductVersion = o.productVersion
+	cfg.ValidateOnStartup = true
+	client := ilicense.NewClient(&cfg)
+	// Load the stored license so a renewal request names it; without a key
+	// or a stored license the request simply omits it.
+	_ = client.Init()
+
+	req, err := client.ActivationRequest()
+	if err != nil {
+		return err
+	}
+	encoded, err := req.Encode()
+	if err != nil {
+		return err
+	}
+	if o.json {
+		return writeJSON(e.stdout, requestResult{Request: req, Encoded: encoded})
+	}
+	fmt.Fprintln(e.stdout, encoded)
+	return nil
+}
+
+func printResults(w io.Writer, asJSON bool, results []inspectResult) error {
+	if asJSON {
+		if len(results) == 1 {
+			return writeJSON(w, results[0])
+		}
+		return writeJSON(w, results)
+	}
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if len(results) > 1 {
+			fmt.Fprintf(w, "[code %d]\n", i+1)
+		}
+		switch {
+		case r.License == nil || r.Verified:
+		case r.Report == nil:
+			fmt.Fprintln(w, "WARNING: signature not verified (no public key given)")
+		default:
+			fmt.Fprintln(w, "WARNING: signature verification failed; the payload below is untrusted")
+		}
+		if r.License != nil {
+			printLicense(w, r.License)
+		}
+		if r.Report != nil {
+			fmt.Fprintln(w, "checks:")
+			for _, c := range r.Report.Checks {
+				line := fmt.Sprintf("  %-11s %s", c.Name, c.Result)
+				if c.Detail != "" {
+					line += " (" + c.Detail + ")"
+				}
+				fmt.Fprintln(w, line)
+			}
+		}
+		if r.Error != "" {
+			fmt.Fprintf(w, "error:          %s\n", r.Error)
+		}
+	}
+	return nil
+}
+
+func printLicense(w io.Writer, l *ilicense.License) {
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-15s %s\n", name+":", value)
+		}
+	}
+	row("license_code", l.LicenseCode)
+	if l.Revision != 0 {
+		row("revision", fmt.Sprint(l.Revision))
+	}
+	row("customer", nameAndCode(l.CustomerName, l.CustomerCode))
+	row("product", nameAndCode(l.ProductName, l.ProductCode))
+	row("product_range", l.ProductVersions)
+	row("issuer", nameAndCode(l.IssuerName, l.IssuerCode))
+	row("issued", formatTime(l.IssueAt))
+	if l.IsPerpetual() {
+		row("expires", "never")
+	} else {
+		row("expires", fmt.Sprintf("%s (%d days left)", formatTime(l.ExpireAt), l.DaysLeft))
+	}
+	row("maintenance", formatTime(l.MaintenanceExpireAt))
+	row("edition", l.EditionName())
+	row("modules", l.Modules)
+	row("edition_mods", strings.Join(l.EditionModules, ","))
+	if l.MaxInstances != 0 {
+		row("max_instances", fmt.Sprint(l.MaxInstances))
+	}
+	if l.AddOn {
+		row("add_on", "true")
+	}
+	if l.Trial {
+		row("trial", "true")
+	}
+	row("limits", formatMap(l.Limits))
+	row("claims", formatMap(l.Claims))
+}
+
+func nameAndCode(name, code string) string {
+	switch {
+	case name == "":
+		return code
+	case code == "":
+		return name
+	default:
+		return name + " (" + code + ")"
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatMap[V any](m map[string]V) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, m[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/issuer"
+)
+
+func runKeygen(e *env, args []string) error {
+	fs := flag.NewFlagSet("ilicense keygen", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	asJSON := fs.Bool("json", false, "print JSON output")
+	bits := fs.Int("bits", issuer.DefaultKeyBits, "RSA key size")
+	out := fs.String("out", "", "directory to write private.pem and public.pem to; prints to stdout when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("keygen takes no arguments")
+	}
+
+	key, err := issuer.GenerateKey(*bits)
+	if err != nil {
+		return err
+	}
+	privatePEM, err := issuer.EncodePrivateKey(key)
+	if err != nil {
+		return err
+	}
+	publicPEM, err := issuer.EncodePublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		if *asJSON {
+			return writeJSON(e.stdout, map[string]string{"private_key": privatePEM, "public_key": publicPEM})
+		}
+		fmt.Fprint(e.stdout, privatePEM, publicPEM)
+		return nil
+	}
+	if err := os.MkdirAll(*out, 0o700); err != nil {
+		return err
+	}
+	privatePath, publicPath := filepath.Join(*out, "private.pem"), filepath.Join(*out, "public.pem")
+	if err := os.WriteFile(privatePath, []byte(privatePEM), 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(publicPath, []byte(publicPEM), 0o644); err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(e.stdout, map[string]string{"private_key_file": privatePath, "public_key_file": publicPath})
+	}
+	fmt.Fprintf(e.stdout, "wrote %s and %s\n", privatePath, publicPath)
+	return nil
+}
+
+func runSign(e *env, args []string) error {
+	fs := flag.NewFlagSet("ilicense sign", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	asJSON := fs.Bool("json", false, "print JSON output")
+	keyFile := fs.String("private-key-file", "", "PEM encoded RSA private key (required)")
+	file := fs.String("file", "", "license JSON to sign; reads stdin when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("sign takes no arguments; pass the license JSON with -file or stdin")
+	}
+	if *keyFile == "" {
+		return errors.New("-private-key-file is required")
+	}
+
+	keyPEM, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+	key, err := issuer.ParsePrivateKey(string(keyPEM))
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if *file != "" {
+		data, err = os.ReadFile(*file)
+	} else {
+		data, err = io.ReadAll(e.stdin)
+	}
+	if err != nil {
+		return err
+	}
+	var license ilicense.License
+	if err := json.Unmarshal(data, &license); err != nil {
+		return fmt.Errorf("invalid license JSON: %w", err)
+	}
+
+	code, err := issuer.New(key).Sign(&license)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(e.stdout, map[string]string{"code": code})
+	}
+	fmt.Fprintln(e.stdout, code)
+	return nil
+}
@@ -0,0 +1,148 @@
+// Command ilicense inspects activation codes and manages the local license store.
+//
+// Usage:
+//
+//	ilicense <command> [flags] [activation-code]
+//
+// Commands:
+//
+//	inspect     decode and print a code or license file, verifying it when a public key is given
+//	verify      verify a code or license file and print the validation report
+//	activate    validate a code and write it to the storage path
+//	status      print the status of the stored license
+//	deactivate  remove the stored license
+//	fingerprint print this machine's ID
+//	request     print an offline activation request to send to the vendor
+//	keygen      generate an RSA key pair for issuing codes
+//	sign        sign license JSON into an activation code
+//
+// Every command accepts -json for machine-readable output. The public key is
+// read from -public-key, -public-key-file or the ILICENSE_PUBLIC_KEY environment variable.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"inspect", "decode and print a code or license file", runInspect},
+		{"verify", "verify a code or license file", runVerify},
+		{"activate", "validate a code and write it to the storage path", runActivate},
+		{"status", "print the status of the stored license", runStatus},
+		{"deactivate", "remove the stored license", runDeactivate},
+		{"fingerprint", "print this machine's ID", runFingerprint},
+		{"request", "print an offline activation request", runRequest},
+		{"keygen", "generate an RSA key pair for issuing codes", runKeygen},
+		{"sign", "sign license JSON into an activation code", runSign},
+	}
+}
+
+// errSilent signals a failure whose details were already printed.
+var errSilent = errors.New("failed")
+
+// env carries process I/O so commands can be tested.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+func run(args []string, e *env) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(e.stderr)
+		return 2
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(e, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 2
+		case errors.Is(err, errSilent):
+			return 1
+		default:
+			fmt.Fprintf(e.stderr, "ilicense %s: %v\n", c.name, err)
+			return 1
+		}
+	}
+	fmt.Fprintf(e.stderr, "ilicense: unknown command %q\n\n", args[0])
+	usage(e.stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: ilicense <command> [flags] [activation-code]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	summaries := make(map[string]string, len(commands))
+	for _, c := range commands {
+		names = append(names, c.name)
+		summaries[c.name] = c.summary
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, summaries[name])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run 'ilicense <command> -h' for command flags")
+}
+
+// readCodes returns activation codes from the argument, a file or stdin.
+// Files may hold several codes, one per line, as written by Client.Activate.
+func readCodes(e *env, args []string, file string) ([]string, error) {
+	var data string
+	switch {
+	case len(args) > 1:
+		return nil, errors.New("expected at most one activation code argument")
+	case len(args) == 1 && file != "":
+		return nil, errors.New("use either an activation code argument or -file")
+	case len(args) == 1:
+		data = args[0]
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		data = string(b)
+	default:
+		b, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return nil, err
+		}
+		data = string(b)
+	}
+	var codes []string
+	for _, line := range strings.Split(data, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			codes = append(codes, line)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, errors.New("no activation code provided")
+	}
+	return codes, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+func runForTest(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &env{stdin: strings.NewReader(""), stdout: &stdout, stderr: &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	code, _, stderr := runForTest("bogus")
+	if code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Fatalf("expected usage error, got %d %q", code, stderr)
+	}
+}
+
+func TestStatusNotActivatedJSON(t *testing.T) {
+	storage := filepath.Join(t.TempDir(), "license.dat")
+	code, stdout, _ := runForTest("status", "-json", "-storage", storage)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	var r statusResult
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", stdout, err)
+	}
+	if r.Status != ilicense.LicenseStatusNotActivated || r.StoragePath != storage {
+		t.Fatalf("unexpected status result: %+v", r)
+	}
+}
+
+func TestInspectRejectsGarbage(t *testing.T) {
+	code, stdout, _ := runForTest("inspect", "-json", "not-a-code")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	var r inspectResult
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil || r.Error == "" || r.License != nil {
+		t.Fatalf("expected decode error in output, got %q", stdout)
+	}
+}
+
+func TestKeygenSignActivate(t *testing.T) {
+	dir := t.TempDir()
+	if code, _, stderr := runForTest("keygen", "-bits", "2048", "-out", dir); code != 0 {
+		t.Fatalf("keygen failed: %s", stderr)
+	}
+	licenseFile := filepath.Join(dir, "license.json")
+	if err := os.WriteFile(licenseFile, []byte(`{"license_code":"L1","customer_name":"ACME","modules":"m-a"}`), 0o600); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	code, stdout, stderr := runForTest("sign", "-private-key-file", filepath.Join(dir, "private.pem"), "-file", licenseFile)
+	if code != 0 {
+		t.Fatalf("sign failed: %s", stderr)
+	}
+	activationCode := strings.TrimSpace(stdout)
+
+	storage := filepath.Join(dir, "license.dat")
+	publicKeyFile := filepath.Join(dir, "public.pem")
+	if code, _, stderr := runForTest("activate", "-public-key-file", publicKeyFile, "-storage", storage, activationCode); code != 0 {
+		t.Fatalf("activate failed: %s", stderr)
+	}
+
+	code, stdout, _ = runForTest("status", "-json", "-public-key-file", publicKeyFile, "-storage", storage)
+	var r statusResult
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil || code != 0 {
+		t.Fatalf("unexpected status output %q (exit %d): %v", stdout, code, err)
+	}
+	if r.Status != ilicense.LicenseStatusValid || r.License.CustomerName != "ACME" {
+		t.Fatalf("unexpected status result: %+v", r)
+	}
+}
+
+func TestInspectWrongKeyWarnsAndFails(t *testing.T) {
+	dir := t.TempDir()
+	if code, _, stderr := runForTest("keygen", "-bits", "2048", "-out", dir); code != 0 {
+		t.Fatalf("keygen failed: %s", stderr)
+	}
+	licenseFile := filepath.Join(dir, "license.json")
+	if err := os.WriteFile(licenseFile, []byte(`{"license_code":"L1"}`), 0o600); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	_, signed, stderr := runForTest("sign", "-private-key-file", filepath.Join(dir, "private.pem"), "-file", licenseFile)
+	signed = strings.TrimSpace(signed)
+	if signed == "" {
+		t.Fatalf("sign failed: %s", stderr)
+	}
+	other := t.TempDir()
+	if code, _, stderr := runForTest("keygen", "-bits", "2048", "-out", other); code != 0 {
+		t.Fatalf("keygen failed: %s", stderr)
+	}
+
+	code, stdout, _ := runForTest("inspect", "-public-key-file", filepath.Join(other, "public.pem"), signed)
+	if code != 1 || !strings.Contains(stdout, "WARNING: signature verification failed") || !strings.Contains(stdout, "L1") {
+		t.Fatalf("expected untrusted payload with warning and exit 1, got %d %q", code, stdout)
+	}
+
+	codesFile := filepath.Join(dir, "codes.dat")
+	if err := os.WriteFile(codesFile, []byte("!!!\n"+signed+"\n"), 0o600); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	code, stdout, _ = runForTest("inspect", "-json", "-public-key-file", filepath.Join(dir, "public.pem"), "-file", codesFile)
+	var results []inspectResult
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil || len(results) != 2 {
+		t.Fatalf("expected two JSON results, got %q: %v", stdout, err)
+	}
+	if code != 1 || results[0].Error == "" || results[1].Error != "" || !results[1].Verified {
+		t.Fatalf("expected the error only on the malformed code, got %d %+v", code, results)
+	}
+}
+
+func TestFingerprintAndRequest(t *testing.T) {
+	code, stdout, stderr := runForTest("fingerprint")
+	if code != 0 {
+		t.Skipf("no machine identifier in this environment: %s", stderr)
+	}
+	fingerprint := strings.TrimSpace(stdout)
+
+	code, stdout, stderr = runForTest("request", "-json", "-product-code", "p-a", "-storage", filepath.Join(t.TempDir(), "license.dat"))
+	if code != 0 {
+		t.Fatalf("request failed: %s", stderr)
+	}
+	var r requestResult
+	if err := json.Unmarshal([]byte(stdout), &r); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", stdout, err)
+	}
+	decoded, err := ilicense.DecodeActivationRequest(r.Encoded)
+	if err != nil || decoded.Fingerprint != fingerprint || decoded.ProductCode != "p-a" {
+		t.Fatalf("unexpected request: %+v %v", decoded, err)
+	}
+}
@@ -0,0 +1,160 @@
+// Package ilicensegrpc provides gRPC server interceptors that authorize
+// methods against the license and module checks of an ilicense.Checker.
+//
+// It lives in its own module so the core ilicense package does not depend on gRPC.
+package ilicensegrpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the ErrorInfo domain attached to denied calls.
+const ErrorDomain = "ilicense"
+
+// ErrorInfo reasons attached to denied calls.
+const (
+	ReasonLicenseNotFound    = "LICENSE_NOT_FOUND"
+	ReasonLicenseExpired     = "LICENSE_EXPIRED"
+	ReasonModuleUnauthorized = "MODULE_UNAUTHORIZED"
+	ReasonLicenseInvalid     = "LICENSE_INVALID"
+)
+
+// Options configures the interceptors.
+type Options struct {
+	// Methods maps full method names ("/pkg.Service/Method") or prefixes such
+	// as a proto service ("/pkg.Service/") to required modules. The exact name
+	// wins, then the longest prefix. Prefixes match whole segments, so
+	// "/pkg.Service" does not cover "/pkg.ServiceAdmin/Method". Unmapped
+	// methods only require a valid license.
+	Methods map[string]string
+	// Skip lets matching methods bypass all checks, e.g. "/grpc.health.v1.Health/Check".
+	Skip func(fullMethod string) bool
+}
+
+type authorizer struct {
+	checker  ilicense.Checker
+	methods  map[string]string
+	prefixes []string
+	skip     func(string) bool
+}
+
+func newAuthorizer(checker ilicense.Checker, opts Options) *authorizer {
+	a := &authorizer{checker: checker, methods: make(map[string]string, len(opts.Methods)), skip: opts.Skip}
+	for name, module := range opts.Methods {
+		if !strings.HasPrefix(name, "/") {
+			name = "/" + name
+		}
+		a.methods[name] = module
+		a.prefixes = append(a.prefixes, name)
+	}
+	sort.Slice(a.prefixes, func(i, j int) bool { return len(a.prefixes[i]) > len(a.prefixes[j]) })
+	return a
+}
+
+func (a *authorizer) module(fullMethod string) string {
+	if module, ok := a.methods[fullMethod]; ok {
+		return module
+	}
+	for _, p := range a.prefixes {
+		if matchPrefix(fullMethod, p) {
+			return a.methods[p]
+		}
+	}
+	return ""
+}
+
+// matchPrefix matches whole path segments, so "/pkg.Svc" covers
+// "/pkg.Svc/Method" but not "/pkg.SvcAdmin/Method".
+func matchPrefix(fullMethod, prefix string) bool {
+	if !strings.HasPrefix(fullMethod, prefix) {
+		return false
+	}
+	return strings.HasSuffix(prefix, "/") || len(fullMethod) == len(prefix) || fullMethod[len(prefix)] == '/'
+}
+
+// authorize checks fullMethod and returns ctx carrying the checked license
+// for ilicense.FromContext.
+func (a *authorizer) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if a.skip != nil && a.skip(fullMethod) {
+		return ctx, nil
+	}
+	ctx, err := ilicense.Guard(ctx, a.checker, a.module(fullMethod))
+	if err != nil {
+		return ctx, ToStatus(err)
+	}
+	return ctx, nil
+}
+
+type licenseStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *licenseStream) Context() context.Context { return s.ctx }
+
+// UnaryServerInterceptor authorizes unary calls; handlers read the license
+// with ilicense.FromContext.
+func UnaryServerInterceptor(checker ilicense.Checker, opts Options) grpc.UnaryServerInterceptor {
+	a := newAuthorizer(checker, opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authorizes streaming calls.
+func StreamServerInterceptor(checker ilicense.Checker, opts Options) grpc.StreamServerInterceptor {
+	a := newAuthorizer(checker, opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		if ctx != ss.Context() {
+			ss = &licenseStream{ServerStream: ss, ctx: ctx}
+		}
+		return handler(srv, ss)
+	}
+}
+
+// ToStatus converts an ilicense error into a gRPC status error carrying an
+// ErrorInfo detail: PermissionDenied for unauthorized modules and
+// FailedPrecondition for missing, expired or otherwise invalid licenses.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := codes.FailedPrecondition
+	info := &errdetails.ErrorInfo{Domain: ErrorDomain, Reason: ReasonLicenseInvalid}
+	var moduleErr *ilicense.ModuleUnauthorizedError
+	switch {
+	case errors.As(err, &moduleErr):
+		code = codes.PermissionDenied
+		info.Reason = ReasonModuleUnauthorized
+		info.Metadata = map[string]string{"module": moduleErr.Module}
+	case errors.Is(err, ilicense.ErrModuleUnauthorized):
+		code = codes.PermissionDenied
+		info.Reason = ReasonModuleUnauthorized
+	case errors.Is(err, ilicense.ErrLicenseExpired):
+		info.Reason = ReasonLicenseExpired
+	case errors.Is(err, ilicense.ErrLicenseNotFound):
+		info.Reason = ReasonLicenseNotFound
+	}
+	st, detailErr := status.New(code, err.Error()).WithDetails(info)
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,131 @@
+package ilicensegrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/ilicensetest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func callUnary(t *testing.T, interceptor grpc.UnaryServerInterceptor, method string) error {
+	t.Helper()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req any) (any, error) { return "ok", nil })
+	return err
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("expected ErrorInfo detail in %v", err)
+	return nil
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	checker := ilicense.Static(&ilicense.License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-report"})
+	interceptor := UnaryServerInterceptor(checker, Options{Methods: map[string]string{
+		"/acme.v1.Reports/":      "m-report",
+		"acme.v1.Reports/Export": "m-export",
+	}})
+
+	if err := callUnary(t, interceptor, "/acme.v1.Reports/List"); err != nil {
+		t.Fatalf("expected granted module to pass, got %v", err)
+	}
+	if err := callUnary(t, interceptor, "/acme.v1.Other/Get"); err != nil {
+		t.Fatalf("expected unmapped method with valid license to pass, got %v", err)
+	}
+
+	err := callUnary(t, interceptor, "/acme.v1.Reports/Export")
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	if info := errorInfo(t, err); info.Reason != ReasonModuleUnauthorized || info.Metadata["module"] != "m-export" {
+		t.Fatalf("unexpected error info: %+v", info)
+	}
+}
+
+func TestMethodPrefixMatchesWholeSegments(t *testing.T) {
+	a := newAuthorizer(ilicense.AllowAll(), Options{Methods: map[string]string{
+		"/acme.v1.Reports":  "m-report",
+		"/acme.v1.Billing/": "m-billing",
+	}})
+	for method, want := range map[string]string{
+		"/acme.v1.Reports/List":      "m-report",
+		"/acme.v1.ReportsAdmin/List": "",
+		"/acme.v1.Billing/Charge":    "m-billing",
+		"/acme.v1.BillingX/Charge":   "",
+	} {
+		if got := a.module(method); got != want {
+			t.Fatalf("module(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
+
+func TestStreamInterceptorLicenseErrors(t *testing.T) {
+	cases := []struct {
+		checker ilicense.Checker
+		reason  string
+	}{
+		{ilicense.DenyAll(), ReasonLicenseNotFound},
+		{ilicense.Static(&ilicense.License{ExpireAt: time.Now().Add(-time.Hour)}), ReasonLicenseExpired},
+	}
+	for _, tc := range cases {
+		interceptor := StreamServerInterceptor(tc.checker, Options{Skip: func(m string) bool { return m == "/grpc.health.v1.Health/Check" }})
+		handler := func(srv any, ss grpc.ServerStream) error { return nil }
+
+		ss := &serverStream{ctx: context.Background()}
+		err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/acme.v1.Reports/Watch"}, handler)
+		if status.Code(err) != codes.FailedPrecondition || errorInfo(t, err).Reason != tc.reason {
+			t.Fatalf("expected FailedPrecondition %s, got %v", tc.reason, err)
+		}
+		if err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler); err != nil {
+			t.Fatalf("expected skipped method to pass, got %v", err)
+		}
+	}
+}
+
+func TestInterceptorsAttachCheckedLicense(t *testing.T) {
+	client := ilicensetest.NewClient(t, nil, ilicensetest.NewLicense(ilicensetest.WithModules("m-report")))
+	info := &grpc.StreamServerInfo{FullMethod: "/acme.v1.Reports/Watch"}
+	opts := Options{Methods: map[string]string{"/acme.v1.Reports/": "m-report"}}
+
+	var streamed *ilicense.License
+	err := StreamServerInterceptor(client, opts)(nil, &serverStream{ctx: context.Background()}, info,
+		func(srv any, ss grpc.ServerStream) error {
+			streamed, _ = ilicense.FromContext(ss.Context())
+			return nil
+		})
+	if err != nil || streamed == nil || !streamed.HasModule("m-report") {
+		t.Fatalf("expected stream handler to see the license, got %+v %v", streamed, err)
+	}
+
+	_, err = UnaryServerInterceptor(client, opts)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: info.FullMethod},
+		func(ctx context.Context, req any) (any, error) {
+			license, ok := ilicense.FromContext(ctx)
+			if !ok {
+				t.Fatalf("expected unary handler to see the license")
+			}
+			license.Modules = ""
+			return nil, nil
+		})
+	if err != nil || !client.HasModule("m-report") {
+		t.Fatalf("handler changes must not reach the client, got %v", err)
+	}
+}
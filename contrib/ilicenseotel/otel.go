@@ -0,0 +1,107 @@
+// Package ilicenseotel adapts ilicense client hooks to OpenTelemetry traces
+// and metrics. It lives in its own module so the core SDK does not depend on
+// OpenTelemetry:
+//
+//	hooks, err := ilicenseotel.Hooks(ilicenseotel.Options{})
+//	cfg.Hooks = ilicense.JoinHooks(cfg.Hooks, hooks)
+//
+// Spans cover Init, Activate, Deactivate and storage I/O. Metrics:
+//
+//	ilicense.checks               license and module checks by check and outcome
+//	ilicense.activations          activation attempts by outcome
+//	ilicense.validation.failures  validation failures by ilicense.ErrorCode
+package ilicenseotel
+
+import (
+	"context"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/xbingbo/ilicense-client-go/contrib/ilicenseotel"
+
+// Attribute keys recorded on metrics.
+const (
+	CheckKey   = attribute.Key("ilicense.check")
+	OutcomeKey = attribute.Key("ilicense.outcome")
+	ModuleKey  = attribute.Key("ilicense.module")
+	ReasonKey  = attribute.Key("ilicense.reason")
+)
+
+// Options configures the instrumentation.
+type Options struct {
+	// TracerProvider defaults to otel.GetTracerProvider().
+	TracerProvider trace.TracerProvider
+	// MeterProvider defaults to otel.GetMeterProvider().
+	MeterProvider metric.MeterProvider
+}
+
+// Hooks returns client hooks recording spans and metrics.
+func Hooks(opts Options) (ilicense.Hooks, error) {
+	tp := opts.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	mp := opts.MeterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	tracer := tp.Tracer(ScopeName)
+	meter := mp.Meter(ScopeName)
+
+	checks, err := meter.Int64Counter("ilicense.checks",
+		metric.WithDescription("License and module checks by outcome."))
+	if err != nil {
+		return ilicense.Hooks{}, err
+	}
+	activations, err := meter.Int64Counter("ilicense.activations",
+		metric.WithDescription("Activation attempts by outcome."))
+	if err != nil {
+		return ilicense.Hooks{}, err
+	}
+	failures, err := meter.Int64Counter("ilicense.validation.failures",
+		metric.WithDescription("Activation code and stored license validation failures by reason."))
+	if err != nil {
+		return ilicense.Hooks{}, err
+	}
+
+	return ilicense.Hooks{
+		StartSpan: func(ctx context.Context, name string) (context.Context, func(error)) {
+			ctx, span := tracer.Start(ctx, name)
+			return ctx, func(err error) {
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				span.End()
+			}
+		},
+		OnActivate: func(_ *ilicense.License, err error) {
+			activations.Add(context.Background(), 1, metric.WithAttributes(OutcomeKey.String(outcome(err, "success", "failure"))))
+		},
+		OnValidationFailure: func(err error) {
+			failures.Add(context.Background(), 1, metric.WithAttributes(ReasonKey.String(ilicense.ErrorCode(err))))
+		},
+		OnLicenseCheck: func(err error) {
+			checks.Add(context.Background(), 1, metric.WithAttributes(
+				CheckKey.String("license"), OutcomeKey.String(outcome(err, "granted", "denied"))))
+		},
+		OnModuleCheck: func(module string, err error) {
+			checks.Add(context.Background(), 1, metric.WithAttributes(
+				CheckKey.String("module"), OutcomeKey.String(outcome(err, "granted", "denied")), ModuleKey.String(module)))
+		},
+	}, nil
+}
+
+func outcome(err error, ok, failed string) string {
+	if err != nil {
+		return failed
+	}
+	return ok
+}
@@ -0,0 +1,82 @@
+package ilicenseotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/ilicensetest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHooksRecordSpansAndMetrics(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	hooks, err := Hooks(Options{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected hooks error: %v", err)
+	}
+	cfg := ilicense.DefaultConfig()
+	cfg.Hooks = hooks
+	client := ilicensetest.NewClient(t, &cfg, ilicensetest.NewLicense(ilicensetest.WithModules("m-a")))
+
+	if _, err := client.Activate("not-a-code"); err == nil {
+		t.Fatalf("expected activation of a malformed code to fail")
+	}
+	_ = client.CheckModule("m-a")
+	_ = client.CheckModule("m-b")
+
+	ended := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range spans.Ended() {
+		ended[s.Name()] = s
+	}
+	for _, name := range []string{ilicense.SpanInit, ilicense.SpanStorageLoad, ilicense.SpanActivate} {
+		if ended[name] == nil {
+			t.Fatalf("expected span %s, got %v", name, spans.Ended())
+		}
+	}
+	if got := ended[ilicense.SpanActivate].Status().Code; got != codes.Error {
+		t.Fatalf("expected failed activation span to have error status, got %v", got)
+	}
+	if load, init := ended[ilicense.SpanStorageLoad], ended[ilicense.SpanInit]; load.Parent().SpanID() != init.SpanContext().SpanID() {
+		t.Fatalf("expected storage span to be a child of the init span")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+	sums := map[string]metricdata.Sum[int64]{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sums[m.Name] = m.Data.(metricdata.Sum[int64])
+		}
+	}
+	denied := attribute.NewSet(CheckKey.String("module"), OutcomeKey.String("denied"), ModuleKey.String("m-b"))
+	if v := value(sums["ilicense.checks"], denied); v != 1 {
+		t.Fatalf("expected one denied m-b check, got %d", v)
+	}
+	if v := value(sums["ilicense.activations"], attribute.NewSet(OutcomeKey.String("failure"))); v != 1 {
+		t.Fatalf("expected one failed activation, got %d", v)
+	}
+	if v := value(sums["ilicense.validation.failures"], attribute.NewSet(ReasonKey.String(ilicense.CodeTruncatedPayload))); v != 1 {
+		t.Fatalf("expected one validation failure, got %d", v)
+	}
+}
+
+func value(sum metricdata.Sum[int64], attrs attribute.Set) int64 {
+	for _, dp := range sum.DataPoints {
+		if dp.Attributes.Equals(&attrs) {
+			return dp.Value
+		}
+	}
+	return 0
+}
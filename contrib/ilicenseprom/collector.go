@@ -0,0 +1,161 @@
+// Package ilicenseprom exports license state and client events as Prometheus metrics.
+//
+// It lives in its own module so the core SDK does not depend on Prometheus:
+//
+//	col := ilicenseprom.NewCollector()
+//	cfg.Hooks = col.Hooks()
+//	client := ilicense.NewClient(&cfg)
+//	col.Watch(client)
+//	prometheus.MustRegister(col)
+//
+// Gauges are read from the watched client on every scrape; counters are fed
+// by the hooks.
+package ilicenseprom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+// Source is the client state read on scrape; *ilicense.Client implements it.
+// Status must not have side effects: it runs on every scrape, so
+// CheckLicenseStatus, which logs and audits, is not used.
+type Source interface {
+	Status() (ilicense.LicenseStatus, error)
+	GetCurrentLicense() *ilicense.License
+}
+
+var _ Source = (*ilicense.Client)(nil)
+
+var statuses = []ilicense.LicenseStatus{
+	ilicense.LicenseStatusValid,
+	ilicense.LicenseStatusTrial,
+	ilicense.LicenseStatusExpired,
+	ilicense.LicenseStatusNotActivated,
+}
+
+// Collector is a prometheus.Collector for one license client.
+type Collector struct {
+	mu     sync.RWMutex
+	source Source
+	now    func() time.Time
+
+	expiresIn    *prometheus.Desc
+	status       *prometheus.Desc
+	info         *prometheus.Desc
+	module       *prometheus.Desc
+	maxInstances *prometheus.Desc
+
+	activations *prometheus.CounterVec
+	failures    *prometheus.CounterVec
+	denied      *prometheus.CounterVec
+}
+
+// NewCollector returns a collector with no watched client.
+func NewCollector() *Collector {
+	return &Collector{
+		now: time.Now,
+		expiresIn: prometheus.NewDesc("ilicense_license_expiry_seconds",
+			"Seconds until the effective license expires; negative once expired. Absent for perpetual licenses.", nil, nil),
+		status: prometheus.NewDesc("ilicense_license_status",
+			"License status as an enum: 1 for the current status, 0 otherwise.", []string{"status"}, nil),
+		info: prometheus.NewDesc("ilicense_license_info",
+			"Effective license metadata.", []string{"license_code", "customer_code", "product_code", "edition"}, nil),
+		module: prometheus.NewDesc("ilicense_license_module_info",
+			"Modules granted by the effective license.", []string{"module"}, nil),
+		maxInstances: prometheus.NewDesc("ilicense_license_max_instances",
+			"MaxInstances of the effective license.", nil, nil),
+		activations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ilicense_activation_attempts_total",
+			Help: "Activation attempts by result.",
+		}, []string{"result"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ilicense_validation_failures_total",
+			Help: "Activation code and stored license validation failures by ilicense.ErrorCode.",
+		}, []string{"reason"}),
+		denied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ilicense_module_denied_total",
+			Help: "CheckModule calls that denied a module.",
+		}, []string{"module"}),
+	}
+}
+
+// Watch sets the client whose state is exported on scrape.
+func (c *Collector) Watch(source Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = source
+}
+
+// Hooks returns client hooks feeding the collector's counters.
+func (c *Collector) Hooks() ilicense.Hooks {
+	return ilicense.Hooks{
+		OnActivate: func(_ *ilicense.License, err error) {
+			result := "success"
+			if err != nil {
+				result = "failure"
+			}
+			c.activations.WithLabelValues(result).Inc()
+		},
+		OnValidationFailure: func(err error) {
+			c.failures.WithLabelValues(ilicense.ErrorCode(err)).Inc()
+		},
+		OnModuleCheck: func(module string, err error) {
+			if err != nil {
+				c.denied.WithLabelValues(module).Inc()
+			}
+		},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.expiresIn
+	ch <- c.status
+	ch <- c.info
+	ch <- c.module
+	ch <- c.maxInstances
+	c.activations.Describe(ch)
+	c.failures.Describe(ch)
+	c.denied.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.activations.Collect(ch)
+	c.failures.Collect(ch)
+	c.denied.Collect(ch)
+
+	c.mu.RLock()
+	source := c.source
+	c.mu.RUnlock()
+	if source == nil {
+		return
+	}
+
+	current, _ := source.Status()
+	for _, s := range statuses {
+		v := 0.0
+		if s == current {
+			v = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.status, prometheus.GaugeValue, v, string(s))
+	}
+
+	license := source.GetCurrentLicense()
+	if license == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.info, prometheus.GaugeValue, 1,
+		license.LicenseCode, license.CustomerCode, license.ProductCode, license.EditionName())
+	ch <- prometheus.MustNewConstMetric(c.maxInstances, prometheus.GaugeValue, float64(license.MaxInstances))
+	if !license.IsPerpetual() {
+		ch <- prometheus.MustNewConstMetric(c.expiresIn, prometheus.GaugeValue, license.ExpireAt.Sub(c.now()).Seconds())
+	}
+	for _, m := range license.ModuleNames() {
+		ch <- prometheus.MustNewConstMetric(c.module, prometheus.GaugeValue, 1, m)
+	}
+}
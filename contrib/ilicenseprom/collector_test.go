@@ -0,0 +1,115 @@
+package ilicenseprom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/ilicensetest"
+)
+
+func TestCollectorExportsStateAndEvents(t *testing.T) {
+	col := NewCollector()
+	cfg := ilicense.DefaultConfig()
+	cfg.Hooks = col.Hooks()
+	client := ilicensetest.NewClient(t, &cfg, ilicensetest.NewLicense(
+		ilicensetest.WithModules("m-a", "m-b"),
+		ilicensetest.WithMaxInstances(3),
+	))
+	col.Watch(client)
+
+	if _, err := client.Activate("not-a-code"); err == nil {
+		t.Fatalf("expected activation of a malformed code to fail")
+	}
+	if err := client.CheckModule("m-c"); err == nil {
+		t.Fatalf("expected module m-c to be denied")
+	}
+	_ = client.CheckModule("m-a")
+
+	expected := `
+# HELP ilicense_activation_attempts_total Activation attempts by result.
+# TYPE ilicense_activation_attempts_total counter
+ilicense_activation_attempts_total{result="failure"} 1
+# HELP ilicense_license_max_instances MaxInstances of the effective license.
+# TYPE ilicense_license_max_instances gauge
+ilicense_license_max_instances 3
+# HELP ilicense_license_module_info Modules granted by the effective license.
+# TYPE ilicense_license_module_info gauge
+ilicense_license_module_info{module="m-a"} 1
+ilicense_license_module_info{module="m-b"} 1
+# HELP ilicense_license_status License status as an enum: 1 for the current status, 0 otherwise.
+# TYPE ilicense_license_status gauge
+ilicense_license_status{status="expired"} 0
+ilicense_license_status{status="not_activated"} 0
+ilicense_license_status{status="trial"} 0
+ilicense_license_status{status="valid"} 1
+# HELP ilicense_module_denied_total CheckModule calls that denied a module.
+# TYPE ilicense_module_denied_total counter
+ilicense_module_denied_total{module="m-c"} 1
+# HELP ilicense_validation_failures_total Activation code and stored license validation failures by ilicense.ErrorCode.
+# TYPE ilicense_validation_failures_total counter
+ilicense_validation_failures_total{reason="truncated_payload"} 1
+`
+	names := []string{
+		"ilicense_activation_attempts_total",
+		"ilicense_license_max_instances",
+		"ilicense_license_module_info",
+		"ilicense_license_status",
+		"ilicense_module_denied_total",
+		"ilicense_validation_failures_total",
+	}
+	if err := testutil.CollectAndCompare(col, strings.NewReader(expected), names...); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testutil.CollectAndCount(col, "ilicense_license_expiry_seconds"); n != 1 {
+		t.Fatalf("expected expiry gauge, got %d series", n)
+	}
+}
+
+func TestCollectorWithoutLicense(t *testing.T) {
+	col := NewCollector()
+	col.Watch(ilicensetest.NewClient(t, nil))
+	if n := testutil.CollectAndCount(col, "ilicense_license_expiry_seconds", "ilicense_license_info"); n != 0 {
+		t.Fatalf("expected no license gauges, got %d series", n)
+	}
+	expected := `
+# HELP ilicense_license_status License status as an enum: 1 for the current status, 0 otherwise.
+# TYPE ilicense_license_status gauge
+ilicense_license_status{status="expired"} 0
+ilicense_license_status{status="not_activated"} 1
+ilicense_license_status{status="trial"} 0
+ilicense_license_status{status="valid"} 0
+`
+	if err := testutil.CollectAndCompare(col, strings.NewReader(expected), "ilicense_license_status"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// pollSource fails the test if the side-effecting CheckLicenseStatus is used.
+type pollSource struct {
+	*ilicense.Client
+	t *testing.T
+}
+
+func (s pollSource) CheckLicenseStatus() (ilicense.LicenseStatus, error) {
+	s.t.Fatalf("scrapes must not call CheckLicenseStatus, which logs and audits")
+	return "", nil
+}
+
+func TestCollectDoesNotCheckStatus(t *testing.T) {
+	col := NewCollector()
+	col.Watch(pollSource{Client: ilicensetest.NewClient(t, nil), t: t})
+	expected := `
+# HELP ilicense_license_status License status as an enum: 1 for the current status, 0 otherwise.
+# TYPE ilicense_license_status gauge
+ilicense_license_status{status="expired"} 0
+ilicense_license_status{status="not_activated"} 1
+ilicense_license_status{status="trial"} 0
+ilicense_license_status{status="valid"} 0
+`
+	if err := testutil.CollectAndCompare(col, strings.NewReader(expected), "ilicense_license_status"); err != nil {
+		t.Fatal(err)
+	}
+}
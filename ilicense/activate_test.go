@@ -0,0 +1,248 @@
+package ilicense
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+var testKey = sync.OnceValue(func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+})
+
+func testPublicKey(t *testing.T) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&testKey().PublicKey)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func signTestLicense(t *testing.T, l licensing.License) string {
+	t.Helper()
+	code, err := licensing.Sign(testKey(), &l)
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+	return code
+}
+
+func activationConfig(t *testing.T) Config {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.PublicKey = testPublicKey(t)
+	cfg.StoragePath = filepath.Join(t.TempDir(), "license.dat")
+	cfg.ValidateOnStartup = true
+	cfg.AllowStartWhenExpired = false
+	return cfg
+}
+
+func TestActivatePersistsAndReloads(t *testing.T) {
+	cfg := activationConfig(t)
+	expireAt := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", CustomerName: "ACME", ExpireAt: expireAt, Modules: "m-a"})
+
+	if _, err := NewClient(&cfg).Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+
+	reloaded := NewClient(&cfg)
+	if err := reloaded.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+	license := reloaded.GetCurrentLicense()
+	if license == nil || license.CustomerName != "ACME" || !license.ExpireAt.Equal(expireAt) {
+		t.Fatalf("unexpected reloaded license: %+v", license)
+	}
+	if err := reloaded.CheckModule("m-a"); err != nil {
+		t.Fatalf("expected module m-a, got %v", err)
+	}
+}
+
+func TestActivateAddOnMergesAfterReload(t *testing.T) {
+	cfg := activationConfig(t)
+	now := time.Now()
+	base := signTestLicense(t, licensing.License{LicenseCode: "base", IssueAt: now, ExpireAt: now.AddDate(1, 0, 0), Modules: "m-a", MaxInstances: 2})
+	addOn := signTestLicense(t, licensing.License{LicenseCode: "seats", AddOn: true, ExpireAt: now.AddDate(1, 0, 0), Modules: "m-b", MaxInstances: 3})
+
+	client := NewClient(&cfg)
+	for _, code := range []string{base, addOn} {
+		if _, err := client.Activate(code); err != nil {
+			t.Fatalf("unexpected activate error: %v", err)
+		}
+	}
+
+	reloaded := NewClient(&cfg)
+	if err := reloaded.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+	if n := len(reloaded.Licenses()); n != 2 {
+		t.Fatalf("expected 2 stored licenses, got %d", n)
+	}
+	license := reloaded.GetCurrentLicense()
+	if license.LicenseCode != "base" || license.MaxInstances != 5 || !reloaded.HasModule("m-b") {
+		t.Fatalf("unexpected effective license: %+v", license)
+	}
+}
+
+func TestActivateAddOnRequiresBase(t *testing.T) {
+	cfg := activationConfig(t)
+	addOn := signTestLicense(t, licensing.License{LicenseCode: "report", AddOn: true, ExpireAt: time.Now().AddDate(1, 0, 0), Modules: "m-report"})
+
+	client := NewClient(&cfg)
+	_, err := client.Activate(addOn)
+	var baseErr *AddOnWithoutBaseError
+	if !errors.As(err, &baseErr) || baseErr.LicenseCode != "report" || ErrorCode(err) != CodeAddOnWithoutBase {
+		t.Fatalf("expected AddOnWithoutBaseError, got %v", err)
+	}
+	if err := client.CheckModule("m-report"); !errors.Is(err, ErrLicenseNotFound) {
+		t.Fatalf("expected add-on not to grant modules, got %v", err)
+	}
+	if len(client.Licenses()) != 0 {
+		t.Fatalf("expected nothing to be stored")
+	}
+
+	// A stored add-on without a base, e.g. after editing the file, grants nothing.
+	if err := os.WriteFile(cfg.StoragePath, []byte(addOn+"\n"), 0o600); err != nil {
+		t.Fatalf("write storage: %v", err)
+	}
+	reloaded := NewClient(&cfg)
+	if err := reloaded.Init(); !errors.Is(err, ErrLicenseNotFound) {
+		t.Fatalf("expected orphan add-on to leave the client not activated, got %v", err)
+	}
+	if err := reloaded.CheckModule("m-report"); !errors.Is(err, ErrLicenseNotFound) {
+		t.Fatalf("expected orphan add-on to grant nothing, got %v", err)
+	}
+}
+
+func TestActivateRefusesDowngrade(t *testing.T) {
+	cfg := activationConfig(t)
+	expireAt := time.Now().AddDate(1, 0, 0)
+	v2 := signTestLicense(t, licensing.License{LicenseCode: "L1", Revision: 2, ExpireAt: expireAt, Modules: "m-a,m-b"})
+	v1 := signTestLicense(t, licensing.License{LicenseCode: "L1", Revision: 1, ExpireAt: expireAt, Modules: "m-a"})
+
+	client := NewClient(&cfg)
+	if _, err := client.Activate(v2); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+
+	preview, err := client.PreviewActivation(v1)
+	if err != nil {
+		t.Fatalf("unexpected preview error: %v", err)
+	}
+	if !preview.Downgrade || preview.Replaces.Revision != 2 || len(preview.Diff.ModulesRemoved) != 1 {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	if _, err := client.Activate(v1); !errors.Is(err, ErrLicenseDowngrade) {
+		t.Fatalf("expected ErrLicenseDowngrade, got %v", err)
+	}
+	if !client.HasModule("m-b") {
+		t.Fatalf("expected refused downgrade to keep current license")
+	}
+
+	if _, err := client.ActivateWithOptions(v1, ActivateOptions{Force: true}); err != nil {
+		t.Fatalf("unexpected forced activate error: %v", err)
+	}
+	if client.HasModule("m-b") {
+		t.Fatalf("expected forced downgrade to apply")
+	}
+}
+
+func TestActivateReplacesTrial(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.Trial = TrialConfig{Enabled: true, Modules: []string{"m-trial"}, StatePaths: []string{filepath.Join(t.TempDir(), ".trial")}, Secret: "trial-secret"}
+	client := NewClient(&cfg)
+	if err := client.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().AddDate(1, 0, 0), Modules: "m-a"})
+	if _, err := client.Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+	if status, err := client.CheckLicenseStatus(); status != LicenseStatusValid || err != nil {
+		t.Fatalf("expected valid status, got %q %v", status, err)
+	}
+	if client.HasModule("m-trial") {
+		t.Fatalf("did not expect trial module after activation")
+	}
+}
+
+func TestActivateRejectsWrongProduct(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.ProductCode = "p-a"
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ProductCode: "p-b"})
+
+	_, err := NewClient(&cfg).Activate(code)
+	var mismatch *ProductMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Expected != "p-a" || mismatch.Actual != "p-b" {
+		t.Fatalf("expected ProductMismatchError, got %v", err)
+	}
+}
+
+func TestNewClientEFailsOnInvalidPublicKey(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.PublicKey = "not-a-key"
+	if _, err := NewClientE(&cfg); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Fatalf("expected ErrInvalidPublicKey, got %v", err)
+	}
+
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})
+	if _, err := NewClient(&cfg).Activate(code); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Fatalf("expected lazy client to report ErrInvalidPublicKey, got %v", err)
+	}
+
+	cfg.Enabled = false
+	if _, err := NewClientE(&cfg); err != nil {
+		t.Fatalf("expected disabled client to ignore the key, got %v", err)
+	}
+	cfg = activationConfig(t)
+	if _, err := NewClientE(&cfg); err != nil {
+		t.Fatalf("unexpected error for valid key: %v", err)
+	}
+}
+
+func TestReloadSkipsVerificationOfUnchangedStorage(t *testing.T) {
+	cfg := activationConfig(t)
+	client := NewClient(&cfg)
+	if _, err := client.Activate(signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+
+	// With a key that matches nothing, only a re-verification could fail.
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected key error: %v", err)
+	}
+	client.publicKey = &other.PublicKey
+	if err := client.Init(); err != nil {
+		t.Fatalf("expected unchanged storage to reload without verification, got %v", err)
+	}
+	if license := client.GetCurrentLicense(); license == nil || license.LicenseCode != "L1" || !license.Valid {
+		t.Fatalf("unexpected reloaded license: %+v", license)
+	}
+
+	changed := signTestLicense(t, licensing.License{LicenseCode: "L2", ExpireAt: time.Now().Add(time.Hour)})
+	if err := client.storage.Save(context.Background(), []byte(changed)); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	if err := client.Init(); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected changed storage to be verified again, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package ilicense
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrAuditTampered means an audit log entry does not match its hash chain.
+var ErrAuditTampered = errors.New("audit log tampered")
+
+// AuditConfig enables the local audit log of license operations.
+type AuditConfig struct {
+	Enabled bool `json:"enabled"`
+	// Path is the audit log file; empty uses audit.log beside StoragePath.
+	Path string `json:"path"`
+	// Actor, when set, names who performs each operation, e.g. the signed-in
+	// administrator, and is recorded in AuditEntry.Actor.
+	Actor func() string `json:"-"`
+	// Secret keys the HMAC chaining the entries and is required: without it
+	// anyone able to write the file could edit entries and recompute every
+	// hash. Keep it out of the file's directory.
+	Secret string `json:"-"`
+}
+
+// AuditEvent is the kind of an audit log entry.
+type AuditEvent string
+
+const (
+	AuditActivate       AuditEvent = "activate"
+	AuditActivateFailed AuditEvent = "activate_failed"
+	AuditDeactivate     AuditEvent = "deactivate"
+	AuditStatusChange   AuditEvent = "status_change"
+)
+
+// AuditEntry is one line of the audit log. Hash is an HMAC over every other
+// field, including PrevHash, so editing or reordering entries breaks the chain
+// unless the secret is known.
+type AuditEntry struct {
+	Seq            int           `json:"seq"`
+	Time           time.Time     `json:"time"`
+	Event          AuditEvent    `json:"event"`
+	Actor          string        `json:"actor,omitempty"`
+	LicenseCode    string        `json:"license_code,omitempty"`
+	CustomerCode   string        `json:"customer_code,omitempty"`
+	Status         LicenseStatus `json:"status,omitempty"`
+	PreviousStatus LicenseStatus `json:"previous_status,omitempty"`
+	// Reason is the ErrorCode of a failed activation, e.g. "signature_invalid".
+	Reason   string `json:"reason,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditChainError reports the first entry that breaks the hash chain.
+type AuditChainError struct {
+	Seq  int
+	Line int
+}
+
+func (e *AuditChainError) Error() string {
+	return ErrAuditTampered.Error() + ": entry " + strconv.Itoa(e.Seq) + " at line " + strconv.Itoa(e.Line)
+}
+
+func (e *AuditChainError) Unwrap() error { return ErrAuditTampered }
+
+func (e *AuditEntry) computeHash(secret string) string {
+	c := *e
+	c.Hash = ""
+	data, _ := json.Marshal(c)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func errNoAuditSecret() error {
+	return &ConfigError{Field: "audit.secret", Err: errors.New("a secret is required to key the audit log")}
+}
+
+// ReadAuditLog parses an audit log and verifies its hash chain with the
+// AuditConfig.Secret it was written with. On a broken chain it returns the
+// entries read so far with an *AuditChainError. Truncating the newest entries
+// cannot be detected from the log alone.
+func ReadAuditLog(r io.Reader, secret string) ([]AuditEntry, error) {
+	if secret == "" {
+		return nil, errNoAuditSecret()
+	}
+	var entries []AuditEntry
+	prev := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return entries, &AuditChainError{Seq: len(entries) + 1, Line: line}
+		}
+		if e.Seq != len(entries)+1 || e.PrevHash != prev || !hmac.Equal([]byte(e.Hash), []byte(e.computeHash(secret))) {
+			return entries, &AuditChainError{Seq: e.Seq, Line: line}
+		}
+		entries = append(entries, e)
+		prev = e.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, &LicenseError{Msg: "failed to read audit log", Err: err}
+	}
+	return entries, nil
+}
+
+// AuditLog reads and verifies the audit log. It returns nil, nil when
+// auditing is disabled or nothing has been recorded yet.
+func (m *Client) AuditLog() ([]AuditEntry, error) {
+	if m.audit == nil {
+		return nil, nil
+	}
+	m.audit.mu.Lock()
+	defer m.audit.mu.Unlock()
+	f, err := os.Open(m.audit.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, &LicenseError{Msg: "failed to read audit log", Err: err}
+	}
+	defer f.Close()
+	return ReadAuditLog(f, m.audit.secret)
+}
+
+// auditLog appends hash-chained entries to a file.
+type auditLog struct {
+	mu     sync.Mutex
+	path   string
+	actor  func() string
+	secret string
+	loaded bool
+	seq    int
+	last   string
+	status LicenseStatus
+}
+
+func newAuditLog(cfg *Config) *auditLog {
+	if !cfg.Audit.Enabled {
+		return nil
+	}
+	path := cfg.Audit.Path
+	if path == "" {
+		path = filepath.Join(filepath.Dir(cfg.StoragePath), "audit.log")
+	}
+	return &auditLog{path: path, actor: cfg.Audit.Actor, secret: cfg.Audit.Secret}
+}
+
+// load restores the chain tail and last recorded status from the file.
+func (a *auditLog) load() error {
+	if a.loaded {
+		return nil
+	}
+	f, err := os.Open(a.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+		for scanner.Scan() {
+			var e AuditEntry
+			if json.Unmarshal(scanner.Bytes(), &e) != nil {
+				continue
+			}
+			a.seq, a.last = e.Seq, e.Hash
+			if e.Status != "" {
+				a.status = e.Status
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	a.loaded = true
+	return nil
+}
+
+// append writes e; the caller holds a.mu.
+func (a *auditLog) append(e AuditEntry) error {
+	if a.secret == "" {
+		return errNoAuditSecret()
+	}
+	if err := a.load(); err != nil {
+		return err
+	}
+	if a.actor != nil {
+		e.Actor = a.actor()
+	}
+	e.Seq = a.seq + 1
+	e.PrevHash = a.last
+	e.Hash = e.computeHash(a.secret)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	a.seq, a.last = e.Seq, e.Hash
+	if e.Status != "" {
+		a.status = e.Status
+	}
+	return nil
+}
+
+// auditEvent records an operation. Audit failures are logged, not returned,
+// so they never block licensing itself.
+func (m *Client) auditEvent(e AuditEntry) {
+	if m.audit == nil {
+		return
+	}
+	m.audit.mu.Lock()
+	defer m.audit.mu.Unlock()
+	e.Time = m.now().UTC()
+	if err := m.audit.append(e); err != nil {
+		m.log.Error("failed to write audit log", slog.String("path", m.audit.path), errorAttrs(err))
+	}
+}
+
+// auditStatus records a status transition when status differs from the last
+// recorded one.
+func (m *Client) auditStatus(status LicenseStatus) {
+	if m.audit == nil {
+		return
+	}
+	m.audit.mu.Lock()
+	defer m.audit.mu.Unlock()
+	if err := m.audit.load(); err != nil {
+		m.log.Error("failed to read audit log", slog.String("path", m.audit.path), errorAttrs(err))
+		return
+	}
+	if m.audit.status == status {
+		return
+	}
+	e := AuditEntry{Time: m.now().UTC(), Event: AuditStatusChange, Status: status, PreviousStatus: m.audit.status}
+	if license := m.getCurrentLicense(); license != nil {
+		e.LicenseCode, e.CustomerCode = license.LicenseCode, license.CustomerCode
+	}
+	if err := m.audit.append(e); err != nil {
+		m.log.Error("failed to write audit log", slog.String("path", m.audit.path), errorAttrs(err))
+	}
+}
+
+func (m *Client) auditActivation(license *License, err error) {
+	if m.audit == nil {
+		return
+	}
+	if err != nil {
+		m.auditEvent(AuditEntry{Event: AuditActivateFailed, Reason: ErrorCode(err), Detail: err.Error()})
+		return
+	}
+	m.auditEvent(AuditEntry{Event: AuditActivate, LicenseCode: license.LicenseCode, CustomerCode: license.CustomerCode})
+	m.auditCurrentStatus()
+}
+
+func (m *Client) auditCurrentStatus() {
+	if m.audit == nil {
+		return
+	}
+	status, _ := m.currentStatus()
+	m.auditStatus(status)
+}
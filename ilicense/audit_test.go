@@ -0,0 +1,161 @@
+package ilicense
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+func TestAuditLogRecordsOperations(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.AllowStartWhenExpired = true
+	cfg.Audit = AuditConfig{Enabled: true, Actor: func() string { return "admin" }, Secret: "audit-secret"}
+	client := NewClient(&cfg)
+	if err := client.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+
+	if _, err := client.Activate("not-a-code"); err == nil {
+		t.Fatalf("expected malformed code to fail")
+	}
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", CustomerCode: "C1", ExpireAt: time.Now().Add(time.Hour)})
+	if _, err := client.Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+	if err := client.Deactivate(); err != nil {
+		t.Fatalf("unexpected deactivate error: %v", err)
+	}
+
+	entries, err := client.AuditLog()
+	if err != nil {
+		t.Fatalf("unexpected audit log error: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, string(e.Event)+":"+string(e.Status)+":"+e.LicenseCode)
+		if e.Actor != "admin" {
+			t.Fatalf("expected actor on every entry, got %+v", e)
+		}
+	}
+	want := []string{
+		"status_change:not_activated:",
+		"activate_failed::",
+		"activate::L1",
+		"status_change:valid:L1",
+		"deactivate::L1",
+		"status_change:not_activated:",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected audit entries:\n got %v\nwant %v", got, want)
+	}
+	if entries[1].Reason != CodeTruncatedPayload {
+		t.Fatalf("expected failure reason, got %+v", entries[1])
+	}
+
+	// A restarted client continues the chain without repeating the status.
+	restarted := NewClient(&cfg)
+	if err := restarted.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+	if entries, err := restarted.AuditLog(); err != nil || len(entries) != len(want) {
+		t.Fatalf("expected unchanged audit log after restart, got %d entries, %v", len(entries), err)
+	}
+}
+
+func TestAuditLogDetectsTampering(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.Audit = AuditConfig{Enabled: true, Secret: "audit-secret"}
+	client := NewClient(&cfg)
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})
+	if _, err := client.Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+
+	path := filepath.Join(filepath.Dir(cfg.StoragePath), "audit.log")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	tampered := strings.Replace(string(data), `"license_code":"L1"`, `"license_code":"L2"`, 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatalf("write audit log: %v", err)
+	}
+
+	entries, err := client.AuditLog()
+	var chainErr *AuditChainError
+	if !errors.Is(err, ErrAuditTampered) || !errors.As(err, &chainErr) || chainErr.Seq != 1 || len(entries) != 0 {
+		t.Fatalf("expected tampering at entry 1, got %v (%d entries)", err, len(entries))
+	}
+}
+
+func TestStatusDoesNotAudit(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.Audit = AuditConfig{Enabled: true, Secret: "audit-secret"}
+	client := NewClient(&cfg)
+	client.setCurrentLicense(&License{LicenseCode: "L1", ExpireAt: time.Now().Add(-time.Hour)})
+
+	if status, err := client.Status(); status != LicenseStatusExpired || !errors.Is(err, ErrLicenseExpired) {
+		t.Fatalf("expected expired status, got %q %v", status, err)
+	}
+	if entries, err := client.AuditLog(); err != nil || len(entries) != 0 {
+		t.Fatalf("expected Status not to write audit entries, got %d %v", len(entries), err)
+	}
+}
+
+func TestAuditLogRejectsRecomputedHashes(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.Audit = AuditConfig{Enabled: true, Secret: "audit-secret"}
+	client := NewClient(&cfg)
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})
+	if _, err := client.Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+
+	// Rewrite the log with a consistent chain under a guessed secret.
+	path := filepath.Join(filepath.Dir(cfg.StoragePath), "audit.log")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	var forged []string
+	prev := ""
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e AuditEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("parse audit entry: %v", err)
+		}
+		e.LicenseCode = strings.Replace(e.LicenseCode, "L1", "L2", 1)
+		e.PrevHash = prev
+		e.Hash = e.computeHash("guess")
+		prev = e.Hash
+		out, _ := json.Marshal(e)
+		forged = append(forged, string(out))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(forged, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("write audit log: %v", err)
+	}
+
+	if _, err := client.AuditLog(); !errors.Is(err, ErrAuditTampered) {
+		t.Fatalf("expected forged chain to be rejected, got %v", err)
+	}
+	if _, err := ReadAuditLog(strings.NewReader(strings.Join(forged, "\n")), "guess"); err != nil {
+		t.Fatalf("expected chain to verify under its own secret, got %v", err)
+	}
+}
+
+func TestAuditRequiresSecret(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.Audit = AuditConfig{Enabled: true}
+	if _, err := NewClientE(&cfg); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected config error without audit secret, got %v", err)
+	}
+	if _, err := ReadAuditLog(strings.NewReader(""), ""); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected config error reading without secret, got %v", err)
+	}
+}
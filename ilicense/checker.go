@@ -0,0 +1,129 @@
+package ilicense
+
+import (
+	"context"
+	"time"
+)
+
+// Checker is the read-only license API used to gate features.
+// *Client implements it; depend on Checker to allow stubs and wrappers.
+type Checker interface {
+	CheckLicense() error
+	CheckModule(moduleName string) error
+	CheckLicenseStatus() (LicenseStatus, error)
+	GetCurrentLicense() *License
+	IsValid() bool
+	HasModule(moduleName string) bool
+}
+
+var _ Checker = (*Client)(nil)
+
+// StatusOf returns the status of c without side effects when c has a Status
+// method like *Client, and falls back to CheckLicenseStatus otherwise.
+func StatusOf(c Checker) (LicenseStatus, error) {
+	if s, ok := c.(interface {
+		Status() (LicenseStatus, error)
+	}); ok {
+		return s.Status()
+	}
+	return c.CheckLicenseStatus()
+}
+
+// Guard checks c like CheckModule, or like CheckLicense when module is empty,
+// and on success returns ctx carrying the license for FromContext. With a
+// *Client the attached license is the snapshot that was checked, copied only
+// when read; other checkers attach GetCurrentLicense.
+func Guard(ctx context.Context, c Checker, module string) (context.Context, error) {
+	if g, ok := c.(interface {
+		Guard(ctx context.Context, module string) (context.Context, error)
+	}); ok {
+		return g.Guard(ctx, module)
+	}
+	var err error
+	if module == "" {
+		err = c.CheckLicense()
+	} else {
+		err = c.CheckModule(module)
+	}
+	if err != nil {
+		return ctx, err
+	}
+	if license := c.GetCurrentLicense(); license != nil {
+		ctx = NewContext(ctx, license)
+	}
+	return ctx, nil
+}
+
+// AllowAll returns a Checker that grants every module, e.g. for development builds.
+// GetCurrentLicense returns nil.
+func AllowAll() Checker { return allowAll{} }
+
+// DenyAll returns a Checker that behaves like a client that was never activated.
+func DenyAll() Checker { return denyAll{} }
+
+// Static returns a Checker backed by a fixed license, evaluated against the
+// system clock. A nil license behaves like DenyAll.
+func Static(license *License) Checker {
+	if license == nil {
+		return denyAll{}
+	}
+	return &staticChecker{license: license.clone()}
+}
+
+type allowAll struct{}
+
+func (allowAll) CheckLicense() error                        { return nil }
+func (allowAll) CheckModule(string) error                   { return nil }
+func (allowAll) CheckLicenseStatus() (LicenseStatus, error) { return LicenseStatusValid, nil }
+func (allowAll) GetCurrentLicense() *License                { return nil }
+func (allowAll) IsValid() bool                              { return true }
+func (allowAll) HasModule(string) bool                      { return true }
+
+type denyAll struct{}
+
+func (denyAll) CheckLicense() error         { return ErrLicenseNotFound }
+func (denyAll) CheckModule(string) error    { return ErrLicenseNotFound }
+func (denyAll) GetCurrentLicense() *License { return nil }
+func (denyAll) IsValid() bool               { return false }
+func (denyAll) HasModule(string) bool       { return false }
+func (denyAll) CheckLicenseStatus() (LicenseStatus, error) {
+	return LicenseStatusNotActivated, ErrLicenseNotFound
+}
+
+type staticChecker struct {
+	license *License
+}
+
+func (s *staticChecker) CheckLicense() error {
+	if s.license.IsExpired(time.Now()) {
+		return ErrLicenseExpired
+	}
+	return nil
+}
+
+func (s *staticChecker) CheckModule(moduleName string) error {
+	if err := s.CheckLicense(); err != nil {
+		return err
+	}
+	if !s.license.HasModule(moduleName) {
+		return &ModuleUnauthorizedError{Module: moduleName}
+	}
+	return nil
+}
+
+func (s *staticChecker) CheckLicenseStatus() (LicenseStatus, error) {
+	switch {
+	case s.license.IsExpired(time.Now()):
+		return LicenseStatusExpired, ErrLicenseExpired
+	case s.license.Trial:
+		return LicenseStatusTrial, nil
+	default:
+		return LicenseStatusValid, nil
+	}
+}
+
+func (s *staticChecker) GetCurrentLicense() *License { return s.license.clone() }
+
+func (s *staticChecker) IsValid() bool { return !s.license.IsExpired(time.Now()) }
+
+func (s *staticChecker) HasModule(moduleName string) bool { return s.license.HasModule(moduleName) }
@@ -0,0 +1,33 @@
+package ilicense
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReadyMadeCheckers(t *testing.T) {
+	if err := AllowAll().CheckModule("anything"); err != nil {
+		t.Fatalf("expected AllowAll to grant modules, got %v", err)
+	}
+	if err := DenyAll().CheckModule("m-a"); !errors.Is(err, ErrLicenseNotFound) {
+		t.Fatalf("expected DenyAll to report ErrLicenseNotFound, got %v", err)
+	}
+	if status, _ := Static(nil).CheckLicenseStatus(); status != LicenseStatusNotActivated {
+		t.Fatalf("expected nil static license to be not activated, got %q", status)
+	}
+
+	license := &License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-a"}
+	static := Static(license)
+	license.Modules = "m-b"
+
+	if err := static.CheckModule("m-a"); err != nil {
+		t.Fatalf("expected static checker to grant m-a, got %v", err)
+	}
+	if err := static.CheckModule("m-b"); !errors.Is(err, ErrModuleUnauthorized) {
+		t.Fatalf("expected static checker to copy its license, got %v", err)
+	}
+	if err := Static(&License{ExpireAt: time.Now().Add(-time.Hour)}).CheckLicense(); !errors.Is(err, ErrLicenseExpired) {
+		t.Fatalf("expected ErrLicenseExpired, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package ilicense
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// Claim returns the custom claim stored under key converted to T.
+// Values that are not directly of type T are converted through their JSON form,
+// so structs, slices and numeric types can be requested as well.
+func Claim[T any](l *License, key string) (T, bool) {
+	var zero T
+	if l == nil {
+		return zero, false
+	}
+	v, ok := l.Claims[key]
+	if !ok {
+		return zero, false
+	}
+	if typed, ok := v.(T); ok {
+		return typed, true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return zero, false
+	}
+	var out T
+	if err := json.Unmarshal(data, &out); err != nil {
+		return zero, false
+	}
+	return out, true
+}
+
+// StringClaim returns a string claim.
+func (l *License) StringClaim(key string) (string, bool) {
+	return Claim[string](l, key)
+}
+
+// IntClaim returns an integer claim. Non-integral numbers are rejected.
+func (l *License) IntClaim(key string) (int64, bool) {
+	if l == nil {
+		return 0, false
+	}
+	switch v := l.Claims[key].(type) {
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case float64:
+		// float64(math.MaxInt64) rounds up to 2^63, which does not fit.
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= 1<<63 {
+			return 0, false
+		}
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// DecodeClaims decodes all custom claims into v, which must be a pointer
+// to a struct or map understood by encoding/json.
+func (l *License) DecodeClaims(v any) error {
+	if l == nil {
+		return errors.New("license is nil")
+	}
+	data, err := json.Marshal(l.Claims)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func copyClaimMap(in map[string]any) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = copyClaimValue(v)
+	}
+	return out
+}
+
+func copyClaimValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return copyClaimMap(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = copyClaimValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
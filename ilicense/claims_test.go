@@ -0,0 +1,77 @@
+package ilicense
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestTypedClaims(t *testing.T) {
+	l := &License{Claims: map[string]any{
+		"region":  "cn-north",
+		"seats":   json.Number("9007199254740993"),
+		"ratio":   1.5,
+		"support": map[string]any{"tier": "gold", "hours": json.Number("24")},
+	}}
+
+	if region, ok := l.StringClaim("region"); !ok || region != "cn-north" {
+		t.Fatalf("expected region claim, got %q %v", region, ok)
+	}
+	if seats, ok := l.IntClaim("seats"); !ok || seats != 9007199254740993 {
+		t.Fatalf("expected exact seats claim, got %d %v", seats, ok)
+	}
+	if _, ok := l.IntClaim("ratio"); ok {
+		t.Fatalf("did not expect non-integral claim to convert")
+	}
+	if _, ok := l.StringClaim("missing"); ok {
+		t.Fatalf("did not expect missing claim")
+	}
+
+	type support struct {
+		Tier  string `json:"tier"`
+		Hours int    `json:"hours"`
+	}
+	s, ok := Claim[support](l, "support")
+	if !ok || s.Tier != "gold" || s.Hours != 24 {
+		t.Fatalf("unexpected support claim: %+v %v", s, ok)
+	}
+
+	var all struct {
+		Region string `json:"region"`
+	}
+	if err := l.DecodeClaims(&all); err != nil || all.Region != "cn-north" {
+		t.Fatalf("unexpected decode result: %+v %v", all, err)
+	}
+}
+
+func TestGetCurrentLicenseCopiesClaims(t *testing.T) {
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{Claims: map[string]any{"edition": "pro"}})
+
+	snapshot := client.GetCurrentLicense()
+	snapshot.Claims["edition"] = "enterprise"
+
+	if got, _ := client.GetCurrentLicense().StringClaim("edition"); got != "pro" {
+		t.Fatalf("expected stored claim to be unchanged, got %q", got)
+	}
+}
+
+func TestIntClaimFloatRange(t *testing.T) {
+	l := &License{Claims: map[string]any{
+		"max":   float64(1 << 62),
+		"min":   float64(math.MinInt64),
+		"over":  float64(1 << 63),
+		"under": -float64(1<<63) * 2,
+	}}
+	if n, ok := l.IntClaim("max"); !ok || n != 1<<62 {
+		t.Fatalf("expected 2^62, got %d %v", n, ok)
+	}
+	if n, ok := l.IntClaim("min"); !ok || n != math.MinInt64 {
+		t.Fatalf("expected MinInt64, got %d %v", n, ok)
+	}
+	for _, key := range []string{"over", "under"} {
+		if n, ok := l.IntClaim(key); ok {
+			t.Fatalf("expected %s to be rejected, got %d", key, n)
+		}
+	}
+}
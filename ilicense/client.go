@@ -1,77 +1,177 @@
 package ilicense
 
 import (
-	"errors"
-	"os"
-	"path/filepath"
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"log/slog"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
 )
 
 type Client struct {
-	config     *Config
-	mu         sync.RWMutex
-	licensePtr *License
+	config  *Config
+	storage Storage
+	clock   Clock
+	log     *slog.Logger
+	audit   *auditLog
+	// publicKey is parsed once by NewClient; keyErr records why it failed.
+	publicKey *rsa.PublicKey
+	keyErr    error
+	// stored remembers the last verified storage content.
+	stored atomic.Pointer[storedSet]
+	// state is swapped as a whole; readers never lock.
+	state atomic.Pointer[licenseState]
+	// activateMu serializes read-modify-write of the stored license set.
+	activateMu sync.Mutex
 }
 
 // NewClient creates a license client with the provided config.
 // If config is nil, DefaultConfig() is applied.
 // The input config is copied so later caller-side changes do not affect client behavior.
+// The public key is parsed once here; an invalid key is reported by every
+// later validation. Use NewClientE to fail at construction instead.
 func NewClient(config *Config) *Client {
 	var cfg Config
 	if config == nil {
 		cfg = DefaultConfig()
 	} else {
 		cfg = *config
+		cfg.Editions = cfg.Editions.clone()
+		cfg.Health.Degraded = slices.Clone(cfg.Health.Degraded)
+		cfg.Health.Unhealthy = slices.Clone(cfg.Health.Unhealthy)
 	}
-	return &Client{
-		config: &cfg,
+	client := &Client{
+		config:  &cfg,
+		storage: cfg.Storage,
+		clock:   cfg.Clock,
+		log:     newLogger(&cfg),
+		audit:   newAuditLog(&cfg),
 	}
+	if client.storage == nil {
+		client.storage = FileStorage{Path: cfg.StoragePath}
+	}
+	if client.clock == nil {
+		client.clock = systemClock{}
+	}
+	client.publicKey, client.keyErr = licensing.ParsePublicKey(cfg.PublicKey)
+	if client.keyErr != nil && cfg.Enabled && cfg.PublicKey != "" {
+		client.log.Warn("invalid license public key", errorAttrs(client.keyErr))
+	}
+	return client
+}
+
+// NewClientE is NewClient that fails fast with a *ValidationError when license
+// validation is enabled and Config.PublicKey cannot be parsed, or with a
+// *ConfigError when auditing is enabled without Audit.Secret.
+func NewClientE(config *Config) (*Client, error) {
+	client := NewClient(config)
+	if client.config.Enabled && client.keyErr != nil {
+		return nil, client.keyErr
+	}
+	if client.audit != nil && client.audit.secret == "" {
+		return nil, errNoAuditSecret()
+	}
+	return client, nil
 }
 
 // Init performs startup checks based on config flags.
 func (m *Client) Init() error {
+	return m.InitContext(context.Background())
+}
+
+// InitContext is Init honoring ctx cancellation and deadlines in storage and hooks.
+func (m *Client) InitContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ctx, end := m.startSpan(ctx, SpanInit)
+	err := m.init(ctx)
+	end(err)
+	if m.config.Enabled {
+		m.auditCurrentStatus()
+	}
+	return err
+}
+
+func (m *Client) init(ctx context.Context) error {
 	if !m.config.Enabled {
-		m.logf("license validation disabled")
+		m.log.Info("license validation disabled")
 		return nil
 	}
 	if m.config.ValidateOnStartup {
-		return m.performStartupValidation()
+		return m.performStartupValidation(ctx)
+	}
+	if m.config.Trial.Enabled {
+		// Load any activated license first so it is not shadowed by the trial.
+		if err := m.loadLicenseFromFile(ctx); err != nil {
+			m.log.Error("license initialization failed", errorAttrs(err))
+			if ctx.Err() != nil {
+				return err
+			}
+		}
+		if m.getCurrentLicense() == nil {
+			return m.initTrial()
+		}
 	}
 	return nil
 }
 
-func (m *Client) performStartupValidation() error {
-	if err := m.loadLicenseFromFile(); err != nil {
-		m.logf("license initialization failed: %v", err)
+func (m *Client) initTrial() error {
+	if err := m.startTrial(); err != nil {
+		m.log.Error("trial initialization failed", errorAttrs(err))
 		if !m.config.AllowStartWhenExpired {
 			return err
 		}
+	}
+	return nil
+}
+
+func (m *Client) performStartupValidation(ctx context.Context) error {
+	if err := m.loadLicenseFromFile(ctx); err != nil {
+		m.log.Error("license initialization failed", errorAttrs(err))
+		if !m.config.AllowStartWhenExpired || ctx.Err() != nil {
+			return err
+		}
 		return nil
 	}
 
 	license := m.getCurrentLicense()
+	if license == nil && m.config.Trial.Enabled {
+		if err := m.initTrial(); err != nil {
+			return err
+		}
+		license = m.getCurrentLicense()
+	}
 	if license == nil {
 		return m.handleNoLicense()
 	}
-	if license.IsExpired(time.Now()) {
-		return m.handleExpiredLicense()
+	if license.IsExpired(m.now()) {
+		return m.handleExpiredLicense(license)
+	}
+	if !license.Trial {
+		m.handleValidLicense(*license)
 	}
-	m.handleValidLicense(*license)
 	return nil
 }
 
 func (m *Client) handleNoLicense() error {
-	m.logln("system not activated - please upload a license activation code to activate")
+	m.log.Warn("system not activated - please upload a license activation code to activate",
+		slog.String(logKeyStatus, string(LicenseStatusNotActivated)))
 	if !m.config.AllowStartWhenExpired {
 		return ErrLicenseNotFound
 	}
 	return nil
 }
 
-func (m *Client) handleExpiredLicense() error {
+func (m *Client) handleExpiredLicense(license *License) error {
+	m.log.Warn("license expired", licenseAttrs(license),
+		slog.String(logKeyStatus, string(LicenseStatusExpired)), slog.Time("expire_at", license.ExpireAt))
 	if !m.config.AllowStartWhenExpired {
 		return ErrLicenseExpired
 	}
@@ -79,49 +179,115 @@ func (m *Client) handleExpiredLicense() error {
 }
 
 func (m *Client) handleValidLicense(license License) {
-	m.logf("license validation successful - customer: %s, product: %s, expiry: %s, days left: %d",
-		license.CustomerName,
-		license.ProductName,
-		license.ExpireAt.Format("2006-01-02 15:04:04"),
-		license.DaysLeft,
+	m.log.Info("license validation successful", licenseAttrs(&license),
+		slog.String(logKeyStatus, string(LicenseStatusValid)),
+		slog.String("customer_name", license.CustomerName),
+		slog.String("product_name", license.ProductName),
+		slog.Time("expire_at", license.ExpireAt),
+		slog.Int64("days_left", license.DaysLeft),
 	)
 }
 
 // CheckLicenseStatus returns the current runtime license status and a corresponding error.
+// With auditing enabled, a status that differs from the last recorded one is
+// written to the audit log.
 func (m *Client) CheckLicenseStatus() (LicenseStatus, error) {
-	license := m.getCurrentLicense()
-	if license == nil {
-		m.logln("skipping check: not activated")
-		return LicenseStatusNotActivated, ErrLicenseNotFound
+	status, err := m.currentStatus()
+	switch status {
+	case LicenseStatusNotActivated:
+		m.log.Debug("skipping check: not activated", slog.String(logKeyStatus, string(status)))
+	case LicenseStatusExpired:
+		m.log.Warn("periodic check: license expired", slog.String(logKeyStatus, string(status)))
 	}
+	m.auditStatus(status)
+	return status, err
+}
 
-	if license.IsExpired(time.Now()) {
-		m.logln("periodic check: license expired")
+// Status is CheckLicenseStatus without logging or auditing, for callers that
+// poll the status such as metrics, probes and request middleware.
+func (m *Client) Status() (LicenseStatus, error) {
+	return m.currentStatus()
+}
+
+func (m *Client) currentStatus() (LicenseStatus, error) {
+	now := m.now()
+	s := m.loadState(now)
+	if s == nil || s.license == nil {
+		return LicenseStatusNotActivated, ErrLicenseNotFound
+	}
+	if s.expired(now) {
 		return LicenseStatusExpired, ErrLicenseExpired
 	}
+	if s.license.Trial {
+		return LicenseStatusTrial, nil
+	}
 	return LicenseStatusValid, nil
 }
 
 // Activate validates activation code and persists it.
+// A base license replaces the current base license, while an add-on license
+// (License.AddOn) is stored alongside it and merged into the effective entitlement.
+// A successful activation replaces any running trial. Codes older than the
+// license they replace are refused with DowngradeError; see ActivateWithOptions.
 func (m *Client) Activate(activationCode string) (*License, error) {
-	m.logln("starting license activation")
-	raw, err := licensing.Validate(m.config.PublicKey, activationCode)
-	if err != nil {
-		return nil, err
-	}
-	license := fromCoreLicense(raw)
+	return m.ActivateWithOptionsContext(context.Background(), activationCode, ActivateOptions{})
+}
+
+// ActivateContext is Activate honoring ctx cancellation and deadlines in storage and hooks.
+func (m *Client) ActivateContext(ctx context.Context, activationCode string) (*License, error) {
+	return m.ActivateWithOptionsContext(ctx, activationCode, ActivateOptions{})
+}
+
+// Deactivate removes all stored activation codes and unloads the current license.
+// Trial records are kept, so deactivating does not restart a trial.
+func (m *Client) Deactivate() error {
+	return m.DeactivateContext(context.Background())
+}
 
-	if license.IsExpired(time.Now()) {
-		return nil, ErrLicenseExpired
+// DeactivateContext is Deactivate honoring ctx cancellation and deadlines.
+func (m *Client) DeactivateContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
+	previous := m.getCurrentLicense()
+	ctx, end := m.startSpan(ctx, SpanDeactivate)
+	err := m.deactivate(ctx)
+	end(err)
+	if err == nil {
+		entry := AuditEntry{Event: AuditDeactivate}
+		if previous != nil {
+			entry.LicenseCode, entry.CustomerCode = previous.LicenseCode, previous.CustomerCode
+		}
+		m.auditEvent(entry)
+		m.auditCurrentStatus()
+	}
+	return err
+}
 
-	if err := m.saveLicenseToFile(activationCode); err != nil {
-		return nil, err
+func (m *Client) deactivate(ctx context.Context) error {
+	m.activateMu.Lock()
+	defer m.activateMu.Unlock()
+	ctx, end := m.startSpan(ctx, SpanStorageRemove)
+	err := m.storage.Remove(ctx)
+	end(err)
+	if err != nil {
+		return &LicenseError{Msg: "failed to remove license", Err: err}
 	}
+	m.stored.Store(nil)
+	m.setEntries(nil)
+	m.log.Info("license deactivated")
+	return nil
+}
 
-	m.setCurrentLicense(license)
-	m.logf("license activated successfully: %s", license.CustomerName)
-	return license, nil
+// Licenses returns snapshots of every stored license, base license first.
+// GetCurrentLicense returns their merged effective entitlement.
+func (m *Client) Licenses() []*License {
+	entries := m.getEntries()
+	out := make([]*License, len(entries))
+	for i, e := range entries {
+		out[i] = e.license.clone()
+	}
+	return out
 }
 
 // GetCurrentLicense returns a snapshot of the currently loaded license.
@@ -131,126 +297,293 @@ func (m *Client) GetCurrentLicense() *License {
 
 // IsValid reports whether a non-expired license is currently loaded.
 func (m *Client) IsValid() bool {
-	license := m.getCurrentLicense()
-	return license != nil && !license.IsExpired(time.Now())
+	now := m.now()
+	return m.loadState(now).check(now) == nil
 }
 
 // HasModule reports whether the loaded license grants the given module.
 func (m *Client) HasModule(moduleName string) bool {
-	license := m.getCurrentLicense()
-	return license != nil && license.HasModule(moduleName)
+	now := m.now()
+	return m.loadState(now).hasModule(moduleName, now)
+}
+
+// CheckLicenseContext is CheckLicense that first fails with ctx.Err() once ctx is done.
+func (m *Client) CheckLicenseContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.CheckLicense()
 }
 
 // CheckLicense validates that a non-expired license is loaded.
 func (m *Client) CheckLicense() error {
-	license := m.getCurrentLicense()
-	if license == nil {
-		return ErrLicenseNotFound
-	}
-	if license.IsExpired(time.Now()) {
-		return ErrLicenseExpired
+	err := m.checkLicense()
+	m.hookLicenseCheck(err)
+	return err
+}
+
+func (m *Client) checkLicense() error {
+	now := m.now()
+	return m.loadState(now).check(now)
+}
+
+// Guard is CheckModule, or CheckLicense when module is empty, that on success
+// returns ctx carrying the checked snapshot for FromContext. The license is
+// not copied unless a handler reads it.
+func (m *Client) Guard(ctx context.Context, module string) (context.Context, error) {
+	now := m.now()
+	s := m.loadState(now)
+	if module == "" {
+		err := s.check(now)
+		m.hookLicenseCheck(err)
+		if err != nil {
+			return ctx, err
+		}
+	} else {
+		err := s.checkModule(module, now)
+		if err != nil {
+			m.log.Debug("module check denied", slog.String(logKeyModule, module), errorAttrs(err))
+		}
+		m.hookModuleCheck(module, err)
+		if err != nil {
+			return ctx, err
+		}
 	}
-	return nil
+	return newSharedContext(ctx, s.license), nil
 }
 
 // CheckModule validates both license validity and module authorization.
+// It reads a single immutable snapshot and does not allocate when the module
+// is granted and no hooks are configured.
 func (m *Client) CheckModule(moduleName string) error {
-	if err := m.CheckLicense(); err != nil {
+	err := m.checkModule(moduleName)
+	if err != nil {
+		m.log.Debug("module check denied", slog.String(logKeyModule, moduleName), errorAttrs(err))
+	}
+	m.hookModuleCheck(moduleName, err)
+	return err
+}
+
+func (m *Client) checkModule(moduleName string) error {
+	now := m.now()
+	return m.loadState(now).checkModule(moduleName, now)
+}
+
+// CheckVersionEntitled validates that a non-expired license is loaded and that a
+// build released at buildDate is covered by its maintenance period.
+func (m *Client) CheckVersionEntitled(buildDate time.Time) error {
+	now := m.now()
+	s := m.loadState(now)
+	if err := s.check(now); err != nil {
 		return err
 	}
-	license := m.getCurrentLicense()
-	if license == nil || !license.HasModule(moduleName) {
-		return &ModuleUnauthorizedError{Module: moduleName}
+	license := s.license
+	if !license.CoversBuild(buildDate) {
+		return &MaintenanceExpiredError{BuildDate: buildDate, MaintenanceExpireAt: license.MaintenanceExpireAt}
 	}
 	return nil
 }
 
-func (m *Client) loadLicenseFromFile() error {
-	path := m.config.StoragePath
-	if path == "" {
+func (m *Client) loadLicenseFromFile(ctx context.Context) error {
+	ctx, end := m.startSpan(ctx, SpanStorageLoad)
+	data, err := m.storage.Load(ctx)
+	end(err)
+	if err != nil {
+		return &LicenseError{Msg: "failed to load license file", Err: err}
+	}
+	if data == nil {
+		m.log.Debug("no stored license")
 		return nil
 	}
 
-	data, err := os.ReadFile(path)
+	entries, err := m.storedEntries(data)
 	if err != nil {
-		if os.IsNotExist(err) {
-			m.logf("license file does not exist: %s", path)
-			return nil
+		m.hookValidationFailure(err)
+		return err
+	}
+	if len(entries) == 0 {
+		m.log.Debug("stored license is empty")
+		return nil
+	}
+	m.setEntries(entries)
+	m.log.Info("license loaded from storage", slog.Int("licenses", len(entries)))
+	return nil
+}
+
+// storedSet is verified storage content and the licenses it holds.
+type storedSet struct {
+	sum     [sha256.Size]byte
+	entries []storedLicense
+}
+
+// storedEntries verifies stored content, reusing the previous result when the
+// bytes are unchanged so reloads skip signature verification.
+func (m *Client) storedEntries(data []byte) ([]storedLicense, error) {
+	sum := sha256.Sum256(data)
+	if set := m.stored.Load(); set != nil && set.sum == sum {
+		return m.refreshEntries(set.entries), nil
+	}
+	entries, err := m.verifyStoredCodes(string(data))
+	if err != nil {
+		return nil, err
+	}
+	m.stored.Store(&storedSet{sum: sum, entries: entries})
+	return entries, nil
+}
+
+// refreshEntries copies entries with Valid and DaysLeft recomputed for now.
+func (m *Client) refreshEntries(entries []storedLicense) []storedLicense {
+	out := make([]storedLicense, len(entries))
+	for i, e := range entries {
+		license := e.license.clone()
+		m.setDerived(license)
+		out[i] = storedLicense{code: e.code, license: license}
+	}
+	return out
+}
+
+// verifyStoredCodes validates every stored activation code. Content written by
+// older versions may hold a single code wrapped across lines.
+func (m *Client) verifyStoredCodes(data string) ([]storedLicense, error) {
+	codes := splitStoredCodes(data)
+	entries := make([]storedLicense, 0, len(codes))
+	for _, code := range codes {
+		license, err := m.verifyCode(code)
+		if err != nil {
+			if len(codes) > 1 {
+				if legacy, legacyErr := m.verifyCode(data); legacyErr == nil {
+					return []storedLicense{{code: normalizeCode(data), license: legacy}}, nil
+				}
+			}
+			return nil, err
 		}
-		return &LicenseError{Msg: "failed to load license file", Err: err}
+		entries = append(entries, storedLicense{code: code, license: license})
 	}
+	return entries, nil
+}
 
-	raw, err := licensing.Validate(m.config.PublicKey, string(data))
+// verifyCode validates an activation code and checks product binding.
+func (m *Client) verifyCode(code string) (*License, error) {
+	raw, err := m.validate(code)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	license := fromCoreLicense(raw)
-	m.setCurrentLicense(license)
-	m.logln("license loaded successfully from file")
-	return nil
+	license := m.prepareLicense(raw)
+	if err := m.checkProduct(license); err != nil {
+		return nil, err
+	}
+	return license, nil
 }
 
-func (m *Client) saveLicenseToFile(activationCode string) error {
-	path := m.config.StoragePath
-	if path == "" {
-		return &LicenseError{Msg: "failed to save license", Err: errors.New("storage path is empty")}
+// validate verifies code with the key parsed by NewClient. With an invalid
+// key it defers to licensing.Validate, which reports malformed codes first.
+func (m *Client) validate(code string) (*licensing.License, error) {
+	if m.keyErr != nil {
+		return licensing.Validate(m.config.PublicKey, code)
 	}
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return &LicenseError{Msg: "failed to save license", Err: err}
+	return licensing.Verify(m.publicKey, code)
+}
+
+func joinCodes(entries []storedLicense) string {
+	codes := make([]string, len(entries))
+	for i, e := range entries {
+		codes[i] = e.code
 	}
-	if err := os.WriteFile(path, []byte(activationCode), 0o600); err != nil {
+	return strings.Join(codes, "\n")
+}
+
+func (m *Client) saveLicenseToFile(ctx context.Context, content string) error {
+	ctx, end := m.startSpan(ctx, SpanStorageSave)
+	err := m.storage.Save(ctx, []byte(content))
+	end(err)
+	if err != nil {
 		return &LicenseError{Msg: "failed to save license", Err: err}
 	}
-	m.logf("license saved: %s", path)
+	m.log.Debug("license saved")
 	return nil
 }
 
 func (m *Client) getCurrentLicense() *License {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	if m.licensePtr == nil {
+	s := m.loadState(m.now())
+	if s == nil || s.license == nil {
 		return nil
 	}
-	clone := *m.licensePtr
-	return &clone
+	return s.license.clone()
 }
 
+// setCurrentLicense loads license without stored entries, as for a trial.
 func (m *Client) setCurrentLicense(license *License) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.licensePtr = license
+	m.state.Store(newLicenseState(license, nil))
 }
 
-func fromCoreLicense(in *licensing.License) *License {
-	if in == nil {
-		return nil
+func (m *Client) getEntries() []storedLicense {
+	if s := m.state.Load(); s != nil {
+		return s.entries
 	}
-	return &License{
-		LicenseCode:  in.LicenseCode,
-		CustomerCode: in.CustomerCode,
-		CustomerName: in.CustomerName,
-		ProductCode:  in.ProductCode,
-		ProductName:  in.ProductName,
-		IssuerCode:   in.IssuerCode,
-		IssuerName:   in.IssuerName,
-		IssueAt:      in.IssueAt,
-		ExpireAt:     in.ExpireAt,
-		Modules:      in.Modules,
-		MaxInstances: in.MaxInstances,
-		Valid:        in.Valid,
-		DaysLeft:     in.DaysLeft,
+	return nil
+}
+
+// setEntries stores the license set and its merged effective license.
+func (m *Client) setEntries(entries []storedLicense) {
+	m.state.Store(mergeState(entries, m.now()))
+}
+
+// prepareLicense converts a verified license and resolves derived fields.
+func (m *Client) prepareLicense(raw *licensing.License) *License {
+	license := fromCoreLicense(raw)
+	if license == nil {
+		return nil
 	}
+	m.resolveEditions(license)
+	m.setDerived(license)
+	return license
 }
 
-func (m *Client) logf(format string, v ...any) {
-	if m.config != nil && m.config.Logger != nil {
-		m.config.Logger.Printf(format, v...)
+// setDerived recomputes Valid and DaysLeft for the current time.
+func (m *Client) setDerived(license *License) {
+	now := m.now()
+	license.Valid = !license.IsExpired(now)
+	license.DaysLeft = daysLeft(license.ExpireAt, now)
+}
+
+// daysLeft counts whole days from now to expireAt, 0 when expireAt is zero.
+func daysLeft(expireAt, now time.Time) int64 {
+	if expireAt.IsZero() {
+		return 0
 	}
+	return int64(expireAt.Sub(now).Hours() / 24)
+}
+
+func (m *Client) resolveEditions(license *License) {
+	catalog := m.config.Editions.merge(license.Editions)
+	license.EditionModules = catalog.Modules(license.EditionName())
 }
 
-func (m *Client) logln(v ...any) {
-	if m.config != nil && m.config.Logger != nil {
-		m.config.Logger.Println(v...)
+func fromCoreLicense(in *licensing.License) *License {
+	if in == nil {
+		return nil
+	}
+	return &License{
+		LicenseCode:         in.LicenseCode,
+		CustomerCode:        in.CustomerCode,
+		CustomerName:        in.CustomerName,
+		ProductCode:         in.ProductCode,
+		ProductName:         in.ProductName,
+		IssuerCode:          in.IssuerCode,
+		IssuerName:          in.IssuerName,
+		IssueAt:             in.IssueAt,
+		ExpireAt:            in.ExpireAt,
+		MaintenanceExpireAt: in.MaintenanceExpireAt,
+		ProductVersions:     in.ProductVersions,
+		Revision:            in.Revision,
+		AddOn:               in.AddOn,
+		Limits:              in.Limits,
+		Modules:             in.Modules,
+		MaxInstances:        in.MaxInstances,
+		Claims:              in.Claims,
+		Edition:             in.Edition,
+		Editions:            fromCoreEditions(in.Editions),
+		Valid:               in.Valid,
+		DaysLeft:            in.DaysLeft,
 	}
 }
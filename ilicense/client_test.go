@@ -1,7 +1,9 @@
 package ilicense
 
 import (
+	"context"
 	"errors"
+	"os"
 	"testing"
 	"time"
 )
@@ -73,3 +75,50 @@ func TestCheckModuleUnauthorizedError(t *testing.T) {
 		t.Fatalf("expected module m-c, got %s", moduleErr.Module)
 	}
 }
+
+func TestCheckVersionEntitled(t *testing.T) {
+	maintenanceEnd := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{MaintenanceExpireAt: maintenanceEnd})
+
+	if err := client.CheckVersionEntitled(maintenanceEnd.AddDate(0, -1, 0)); err != nil {
+		t.Fatalf("expected older build to be entitled, got %v", err)
+	}
+
+	err := client.CheckVersionEntitled(maintenanceEnd.AddDate(0, 1, 0))
+	if !errors.Is(err, ErrMaintenanceExpired) {
+		t.Fatalf("expected ErrMaintenanceExpired, got %v", err)
+	}
+	var maintenanceErr *MaintenanceExpiredError
+	if !errors.As(err, &maintenanceErr) || !maintenanceErr.MaintenanceExpireAt.Equal(maintenanceEnd) {
+		t.Fatalf("expected MaintenanceExpiredError, got %v", err)
+	}
+
+	// Perpetual runtime use stays valid after maintenance ends.
+	if err := client.CheckLicense(); err != nil {
+		t.Fatalf("expected perpetual license to stay valid, got %v", err)
+	}
+}
+
+func TestDeactivateRemovesStoredLicense(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.StoragePath = t.TempDir() + "/license.dat"
+	client := NewClient(&cfg)
+	if err := client.saveLicenseToFile(context.Background(), "code"); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	client.setEntries([]storedLicense{{code: "code", license: &License{LicenseCode: "L1"}}})
+
+	if err := client.Deactivate(); err != nil {
+		t.Fatalf("unexpected deactivate error: %v", err)
+	}
+	if _, err := os.Stat(cfg.StoragePath); !os.IsNotExist(err) {
+		t.Fatalf("expected license file to be removed, got %v", err)
+	}
+	if status, _ := client.CheckLicenseStatus(); status != LicenseStatusNotActivated {
+		t.Fatalf("expected not activated after deactivate, got %q", status)
+	}
+	if err := client.Deactivate(); err != nil {
+		t.Fatalf("expected repeated deactivate to succeed, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package ilicense
 
-import "os"
+import (
+	"log/slog"
+	"os"
+)
 
 // Logger defines optional logging hook for SDK runtime messages.
 type Logger interface {
@@ -15,7 +18,29 @@ type Config struct {
 	StoragePath           string `json:"storage_path"`
 	ValidateOnStartup     bool   `json:"validate_on_startup"`
 	AllowStartWhenExpired bool   `json:"allow_start_when_expired"`
-	Logger                Logger `json:"-"`
+	// ProductCode, when set, must equal License.ProductCode.
+	ProductCode string `json:"product_code"`
+	// ProductVersion, when set, must satisfy License.ProductVersions.
+	ProductVersion string `json:"product_version"`
+	// Editions is the application-supplied edition catalogue used to resolve
+	// License.Edition into modules. A catalogue embedded in the license wins.
+	Editions EditionCatalog `json:"editions"`
+	Trial    TrialConfig    `json:"trial"`
+	// Audit enables the hash-chained audit log of license operations.
+	Audit AuditConfig `json:"audit"`
+	// Health classifies license statuses for Client.Health and Client.HealthCheck.
+	Health HealthPolicy `json:"health"`
+	// Logger is the legacy printf-style logger, adapted with NewLoggerHandler.
+	Logger Logger `json:"-"`
+	// SlogLogger receives structured, levelled SDK logs and takes precedence
+	// over Logger. Both nil keeps the SDK silent.
+	SlogLogger *slog.Logger `json:"-"`
+	// Storage overrides where activation codes are stored; nil uses StoragePath.
+	Storage Storage `json:"-"`
+	// Clock overrides the time source, e.g. for tests; nil uses the system clock.
+	Clock Clock `json:"-"`
+	// Hooks observe activations, validation failures and module checks.
+	Hooks Hooks `json:"-"`
 }
 
 // DefaultConfig returns the Java-equivalent defaults.
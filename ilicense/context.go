@@ -0,0 +1,32 @@
+package ilicense
+
+import "context"
+
+type licenseContextKey struct{}
+
+// sharedLicense is a license owned by a client snapshot. It is attached to
+// contexts without copying; FromContext hands out copies.
+type sharedLicense License
+
+// NewContext returns a copy of ctx carrying license, e.g. for request
+// handlers behind license middleware.
+func NewContext(ctx context.Context, license *License) context.Context {
+	return context.WithValue(ctx, licenseContextKey{}, license)
+}
+
+func newSharedContext(ctx context.Context, license *License) context.Context {
+	return context.WithValue(ctx, licenseContextKey{}, (*sharedLicense)(license))
+}
+
+// FromContext returns the license stored in ctx by NewContext or Guard.
+func FromContext(ctx context.Context) (*License, bool) {
+	switch license := ctx.Value(licenseContextKey{}).(type) {
+	case *License:
+		return license, license != nil
+	case *sharedLicense:
+		if license != nil {
+			return (*License)(license).clone(), true
+		}
+	}
+	return nil, false
+}
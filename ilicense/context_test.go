@@ -0,0 +1,99 @@
+package ilicense
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+// ctxStorage is a Storage that blocks until ctx is done.
+type ctxStorage struct{ seen context.Context }
+
+func (s *ctxStorage) Load(ctx context.Context) ([]byte, error) {
+	s.seen = ctx
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *ctxStorage) Save(ctx context.Context, _ []byte) error {
+	s.seen = ctx
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *ctxStorage) Remove(ctx context.Context) error { return ctx.Err() }
+
+func TestContextCancellationReachesStorage(t *testing.T) {
+	storage := &ctxStorage{}
+	cfg := activationConfig(t)
+	cfg.AllowStartWhenExpired = true
+	cfg.Storage = storage
+	client := NewClient(&cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.InitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected init to honor the deadline, got %v", err)
+	}
+	if _, ok := storage.seen.Deadline(); !ok {
+		t.Fatalf("expected storage to receive the caller's context")
+	}
+
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.ActivateContext(ctx, code)
+	if !errors.Is(err, context.DeadlineExceeded) || ErrorCode(err) != CodeDeadlineExceeded {
+		t.Fatalf("expected activation to honor the deadline, got %v", err)
+	}
+	if client.GetCurrentLicense() != nil {
+		t.Fatalf("canceled activation must not load the license")
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if err := client.CheckLicenseContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled check, got %v", err)
+	}
+}
+
+func TestLicenseContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatalf("expected no license in an empty context")
+	}
+	ctx := NewContext(context.Background(), &License{LicenseCode: "L1"})
+	if license, ok := FromContext(ctx); !ok || license.LicenseCode != "L1" {
+		t.Fatalf("unexpected license from context: %+v", license)
+	}
+}
+
+func TestGuardAttachesCheckedSnapshot(t *testing.T) {
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour), Modules: "m-a"})
+
+	if _, err := client.Guard(context.Background(), "m-b"); !errors.Is(err, ErrModuleUnauthorized) {
+		t.Fatalf("expected module denial, got %v", err)
+	}
+	ctx, err := client.Guard(context.Background(), "m-a")
+	if err != nil {
+		t.Fatalf("unexpected guard error: %v", err)
+	}
+	// Reloading after the check does not change the license the request saw.
+	client.setCurrentLicense(&License{LicenseCode: "L2", ExpireAt: time.Now().Add(time.Hour)})
+	license, ok := FromContext(ctx)
+	if !ok || license.LicenseCode != "L1" {
+		t.Fatalf("expected the checked license, got %+v", license)
+	}
+	license.LicenseCode = "changed"
+	if again, _ := FromContext(ctx); again.LicenseCode != "L1" {
+		t.Fatalf("FromContext must hand out copies, got %q", again.LicenseCode)
+	}
+
+	ctx, err = Guard(context.Background(), Static(&License{LicenseCode: "S1"}), "")
+	if license, ok := FromContext(ctx); err != nil || !ok || license.LicenseCode != "S1" {
+		t.Fatalf("expected fallback to GetCurrentLicense, got %+v %v", license, err)
+	}
+}
@@ -0,0 +1,95 @@
+package ilicense
+
+import (
+	"strings"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+// Edition describes the modules granted by a product edition.
+// Includes lists editions whose modules are inherited, e.g. Enterprise includes Pro.
+type Edition struct {
+	Modules  []string `json:"modules"`
+	Includes []string `json:"includes,omitempty"`
+}
+
+// EditionCatalog maps edition names to their definitions.
+type EditionCatalog map[string]Edition
+
+// Modules returns all modules granted by edition, including inherited editions.
+// Unknown editions resolve to nil and inheritance cycles are ignored.
+func (c EditionCatalog) Modules(edition string) []string {
+	edition = strings.TrimSpace(edition)
+	if edition == "" || len(c) == 0 {
+		return nil
+	}
+	var out []string
+	seenModule := make(map[string]bool)
+	seenEdition := make(map[string]bool)
+	var walk func(name string)
+	walk = func(name string) {
+		if seenEdition[name] {
+			return
+		}
+		seenEdition[name] = true
+		e, ok := c[name]
+		if !ok {
+			return
+		}
+		for _, m := range e.Modules {
+			m = strings.TrimSpace(m)
+			if m != "" && !seenModule[m] {
+				seenModule[m] = true
+				out = append(out, m)
+			}
+		}
+		for _, inc := range e.Includes {
+			walk(strings.TrimSpace(inc))
+		}
+	}
+	walk(edition)
+	return out
+}
+
+// merge returns a catalogue with entries from override taking precedence.
+func (c EditionCatalog) merge(override EditionCatalog) EditionCatalog {
+	if len(override) == 0 {
+		return c
+	}
+	if len(c) == 0 {
+		return override
+	}
+	out := make(EditionCatalog, len(c)+len(override))
+	for k, v := range c {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
+
+func (c EditionCatalog) clone() EditionCatalog {
+	if c == nil {
+		return nil
+	}
+	out := make(EditionCatalog, len(c))
+	for k, v := range c {
+		out[k] = Edition{
+			Modules:  append([]string(nil), v.Modules...),
+			Includes: append([]string(nil), v.Includes...),
+		}
+	}
+	return out
+}
+
+func fromCoreEditions(in map[string]licensing.Edition) EditionCatalog {
+	if in == nil {
+		return nil
+	}
+	out := make(EditionCatalog, len(in))
+	for k, v := range in {
+		out[k] = Edition{Modules: v.Modules, Includes: v.Includes}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package ilicense
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testCatalog() EditionCatalog {
+	return EditionCatalog{
+		"community":  {Modules: []string{"m-core"}},
+		"pro":        {Modules: []string{"m-report"}, Includes: []string{"community"}},
+		"enterprise": {Modules: []string{"m-audit"}, Includes: []string{"pro", "enterprise"}},
+	}
+}
+
+func TestEditionCatalogModulesInheritance(t *testing.T) {
+	got := testCatalog().Modules("enterprise")
+	want := []string{"m-audit", "m-report", "m-core"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := testCatalog().Modules("unknown"); got != nil {
+		t.Fatalf("expected nil for unknown edition, got %v", got)
+	}
+}
+
+func TestCheckModuleResolvesEdition(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Editions = testCatalog()
+	client := NewClient(&cfg)
+
+	license := &License{ExpireAt: time.Now().Add(time.Hour), Edition: "pro", Modules: "m-extra"}
+	client.resolveEditions(license)
+	client.setCurrentLicense(license)
+
+	for _, module := range []string{"m-extra", "m-report", "m-core"} {
+		if err := client.CheckModule(module); err != nil {
+			t.Fatalf("expected module %s to be granted, got %v", module, err)
+		}
+	}
+	if err := client.CheckModule("m-audit"); !errors.Is(err, ErrModuleUnauthorized) {
+		t.Fatalf("expected ErrModuleUnauthorized, got %v", err)
+	}
+}
+
+func TestLicenseEmbeddedEditionsTakePrecedence(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Editions = testCatalog()
+	client := NewClient(&cfg)
+
+	license := &License{
+		Claims:   map[string]any{"edition": "pro"},
+		Editions: EditionCatalog{"pro": {Modules: []string{"m-signed"}}},
+	}
+	client.resolveEditions(license)
+
+	if !license.HasModule("m-signed") {
+		t.Fatalf("expected module from embedded catalogue")
+	}
+	if license.HasModule("m-core") {
+		t.Fatalf("did not expect module from overridden config edition")
+	}
+}
@@ -0,0 +1,85 @@
+package ilicense
+
+import (
+	"context"
+	"errors"
+)
+
+// Stable error codes returned by ErrorCode, suitable as keys for UI
+// localization and metric labels. Codes are never renamed once released.
+const (
+	CodeLicenseNotFound      = "license_not_found"
+	CodeLicenseExpired       = "license_expired"
+	CodeModuleUnauthorized   = "module_unauthorized"
+	CodeMaintenanceExpired   = "maintenance_expired"
+	CodeProductMismatch      = "product_mismatch"
+	CodeLicenseDowngrade     = "license_downgrade"
+	CodeAddOnWithoutBase     = "addon_without_base"
+	CodeTrialTampered        = "trial_tampered"
+	CodeAuditTampered        = "audit_tampered"
+	CodeUnhealthy            = "unhealthy"
+	CodeMalformedEncoding    = "malformed_encoding"
+	CodeTruncatedPayload     = "truncated_payload"
+	CodeInvalidPublicKey     = "invalid_public_key"
+	CodeUnsupportedAlgorithm = "unsupported_algorithm"
+	CodeInvalidPayload       = "invalid_payload"
+	CodeSignatureInvalid     = "signature_invalid"
+	CodeCanceled             = "canceled"
+	CodeDeadlineExceeded     = "deadline_exceeded"
+	CodeInvalidConfig        = "invalid_config"
+	CodeStorageError         = "storage_error"
+	CodeUnknown              = "unknown"
+)
+
+// ErrorCode returns the stable code of an SDK error, CodeUnknown for errors
+// the SDK does not classify, and "" for nil.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	var licenseErr *LicenseError
+	switch {
+	case errors.Is(err, ErrMalformedEncoding):
+		return CodeMalformedEncoding
+	case errors.Is(err, ErrTruncatedPayload):
+		return CodeTruncatedPayload
+	case errors.Is(err, ErrInvalidPublicKey):
+		return CodeInvalidPublicKey
+	case errors.Is(err, ErrUnsupportedAlgorithm):
+		return CodeUnsupportedAlgorithm
+	case errors.Is(err, ErrInvalidPayload):
+		return CodeInvalidPayload
+	case errors.Is(err, ErrSignatureInvalid):
+		return CodeSignatureInvalid
+	case errors.Is(err, ErrModuleUnauthorized):
+		return CodeModuleUnauthorized
+	case errors.Is(err, ErrProductMismatch):
+		return CodeProductMismatch
+	case errors.Is(err, ErrMaintenanceExpired):
+		return CodeMaintenanceExpired
+	case errors.Is(err, ErrLicenseDowngrade):
+		return CodeLicenseDowngrade
+	case errors.Is(err, ErrAddOnWithoutBase):
+		return CodeAddOnWithoutBase
+	case errors.Is(err, ErrTrialTampered):
+		return CodeTrialTampered
+	case errors.Is(err, ErrInvalidConfig):
+		return CodeInvalidConfig
+	case errors.Is(err, ErrAuditTampered):
+		return CodeAuditTampered
+	case errors.Is(err, ErrLicenseNotFound):
+		return CodeLicenseNotFound
+	case errors.Is(err, ErrLicenseExpired):
+		return CodeLicenseExpired
+	case errors.Is(err, ErrUnhealthy):
+		return CodeUnhealthy
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	case errors.As(err, &licenseErr):
+		return CodeStorageError
+	default:
+		return CodeUnknown
+	}
+}
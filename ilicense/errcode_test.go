@@ -0,0 +1,53 @@
+package ilicense
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+func TestErrorCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code string
+	}{
+		{nil, ""},
+		{ErrLicenseNotFound, CodeLicenseNotFound},
+		{fmt.Errorf("wrapped: %w", ErrLicenseExpired), CodeLicenseExpired},
+		{&ModuleUnauthorizedError{Module: "m-a"}, CodeModuleUnauthorized},
+		{&DowngradeError{}, CodeLicenseDowngrade},
+		{&LicenseError{Msg: "failed to save license", Err: errors.New("disk full")}, CodeStorageError},
+		{&ConfigError{Field: "product_version", Err: errors.New("bad")}, CodeInvalidConfig},
+		{&ValidationError{Stage: StageSignature, Reason: ErrSignatureInvalid}, CodeSignatureInvalid},
+		{&HealthError{Status: LicenseStatusExpired, Err: ErrLicenseExpired}, CodeLicenseExpired},
+		{errors.New("other"), CodeUnknown},
+	}
+	for _, tc := range cases {
+		if got := ErrorCode(tc.err); got != tc.code {
+			t.Fatalf("ErrorCode(%v) = %q, want %q", tc.err, got, tc.code)
+		}
+	}
+}
+
+func TestInvalidProductVersionIsConfigError(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.ProductVersion = "not-a-version"
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ProductVersions: ">=1.0.0", ExpireAt: time.Now().Add(time.Hour)})
+	_, err := NewClient(&cfg).Activate(code)
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "product_version" || ErrorCode(err) != CodeInvalidConfig {
+		t.Fatalf("expected product_version ConfigError, got %v (%s)", err, ErrorCode(err))
+	}
+}
+
+func TestActivateReturnsValidationError(t *testing.T) {
+	cfg := activationConfig(t)
+	_, err := NewClient(&cfg).Activate("not-a-code")
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Stage != StageUnpack || !errors.Is(err, ErrTruncatedPayload) {
+		t.Fatalf("expected truncated payload ValidationError, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package ilicense
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
 	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
 )
@@ -13,10 +15,50 @@ var (
 	ErrLicenseExpired = errors.New("license expired")
 	// ErrModuleUnauthorized means current license does not grant a module.
 	ErrModuleUnauthorized = errors.New("unauthorized module")
+	// ErrMaintenanceExpired means a build was released after the maintenance period ended.
+	ErrMaintenanceExpired = errors.New("maintenance expired")
+	// ErrProductMismatch means the license was issued for another product or version.
+	ErrProductMismatch = errors.New("product mismatch")
+	// ErrLicenseDowngrade means an activation code is older than the license it replaces.
+	ErrLicenseDowngrade = errors.New("license downgrade")
+	// ErrAddOnWithoutBase means an add-on was activated before any base license.
+	ErrAddOnWithoutBase = errors.New("add-on requires a base license")
+	// ErrTrialTampered means a recorded trial start failed integrity verification.
+	ErrTrialTampered = errors.New("trial record tampered")
+	// ErrInvalidConfig means a Config field is unusable, such as an unparsable ProductVersion.
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrMalformedEncoding means the activation code is empty, not base64url,
+	// or carries bytes after the signature.
+	ErrMalformedEncoding = licensing.ErrMalformedEncoding
+	// ErrTruncatedPayload means the decoded activation code is truncated.
+	ErrTruncatedPayload = licensing.ErrTruncatedPayload
+	// ErrInvalidPublicKey means Config.PublicKey cannot be parsed.
+	ErrInvalidPublicKey = licensing.ErrInvalidPublicKey
+	// ErrUnsupportedAlgorithm means Config.PublicKey is not an RSA key.
+	ErrUnsupportedAlgorithm = licensing.ErrUnsupportedAlgorithm
+	// ErrInvalidPayload means the signed payload is not valid license JSON.
+	ErrInvalidPayload = licensing.ErrInvalidPayload
 	// ErrSignatureInvalid means activation code signature verification failed.
 	ErrSignatureInvalid = licensing.ErrSignatureInvalid
 )
 
+// ValidationError reports why an activation code failed validation: the
+// Stage that failed, a Reason sentinel (ErrMalformedEncoding, ErrTruncatedPayload,
+// ErrInvalidPublicKey, ErrUnsupportedAlgorithm, ErrInvalidPayload or
+// ErrSignatureInvalid) and the underlying Err. errors.Is matches both.
+type ValidationError = licensing.ValidationError
+
+// ValidationStage names the validation step that failed.
+type ValidationStage = licensing.ValidationStage
+
+const (
+	StageDecode    = licensing.StageDecode
+	StageUnpack    = licensing.StageUnpack
+	StageKey       = licensing.StageKey
+	StageSignature = licensing.StageSignature
+	StageParse     = licensing.StageParse
+)
+
 // LicenseError wraps a low-level error with context.
 type LicenseError struct {
 	Msg string
@@ -32,6 +74,22 @@ func (e *LicenseError) Error() string {
 
 func (e *LicenseError) Unwrap() error { return e.Err }
 
+// ConfigError reports an unusable Config field, such as "product_version".
+type ConfigError struct {
+	Field string
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	msg := ErrInvalidConfig.Error() + ": " + e.Field
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ConfigError) Unwrap() []error { return []error{ErrInvalidConfig, e.Err} }
+
 // ModuleUnauthorizedError contains the unauthorized module name.
 type ModuleUnauthorizedError struct {
 	Module string
@@ -45,3 +103,64 @@ func (e *ModuleUnauthorizedError) Error() string {
 }
 
 func (e *ModuleUnauthorizedError) Unwrap() error { return ErrModuleUnauthorized }
+
+// MaintenanceExpiredError reports a build released after maintenance ended.
+type MaintenanceExpiredError struct {
+	BuildDate           time.Time
+	MaintenanceExpireAt time.Time
+}
+
+func (e *MaintenanceExpiredError) Error() string {
+	return ErrMaintenanceExpired.Error() + ": build " + e.BuildDate.Format("2006-01-02") +
+		" released after " + e.MaintenanceExpireAt.Format("2006-01-02")
+}
+
+func (e *MaintenanceExpiredError) Unwrap() error { return ErrMaintenanceExpired }
+
+// ProductMismatchError reports a license bound to another product or version range.
+// Field is "product_code" or "product_version".
+type ProductMismatchError struct {
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (e *ProductMismatchError) Error() string {
+	return ErrProductMismatch.Error() + ": " + e.Field + " expected " + e.Expected + ", got " + e.Actual
+}
+
+func (e *ProductMismatchError) Unwrap() error { return ErrProductMismatch }
+
+// DowngradeError reports an activation refused because Candidate is older than Current.
+type DowngradeError struct {
+	Current   *License
+	Candidate *License
+}
+
+func (e *DowngradeError) Error() string {
+	if e.Current == nil || e.Candidate == nil {
+		return ErrLicenseDowngrade.Error()
+	}
+	if e.Current.LicenseCode == e.Candidate.LicenseCode {
+		return ErrLicenseDowngrade.Error() + ": " + e.Candidate.LicenseCode + " revision " +
+			strconv.Itoa(e.Candidate.Revision) + " is older than stored revision " + strconv.Itoa(e.Current.Revision)
+	}
+	return ErrLicenseDowngrade.Error() + ": " + e.Candidate.LicenseCode + " was issued before stored license " + e.Current.LicenseCode
+}
+
+func (e *DowngradeError) Unwrap() error { return ErrLicenseDowngrade }
+
+// AddOnWithoutBaseError reports an add-on activation refused because no base
+// license is stored to extend.
+type AddOnWithoutBaseError struct {
+	LicenseCode string
+}
+
+func (e *AddOnWithoutBaseError) Error() string {
+	if e.LicenseCode == "" {
+		return ErrAddOnWithoutBase.Error()
+	}
+	return ErrAddOnWithoutBase.Error() + ": " + e.LicenseCode
+}
+
+func (e *AddOnWithoutBaseError) Unwrap() error { return ErrAddOnWithoutBase }
@@ -0,0 +1,117 @@
+package ilicense
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// machineIDPaths lists files holding a stable per-installation machine ID.
+var machineIDPaths = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// Fingerprint returns a stable identifier of this machine: a SHA-256 digest of
+// the OS machine ID when one is available, otherwise of the hostname and the
+// hardware addresses of its network interfaces. The raw identifiers are never
+// exposed. Licenses are not bound to it; it identifies the requesting machine
+// in an ActivationRequest.
+func Fingerprint() (string, error) {
+	source, err := machineSource()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte("ilicense-fingerprint|" + source))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func machineSource() (string, error) {
+	for _, path := range machineIDPaths {
+		if data, err := os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return "machine-id:" + id, nil
+			}
+		}
+	}
+	hostname, _ := os.Hostname()
+	var addrs []string
+	if ifaces, err := net.Interfaces(); err == nil {
+		for _, iface := range ifaces {
+			if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+				continue
+			}
+			addrs = append(addrs, iface.HardwareAddr.String())
+		}
+	}
+	if hostname == "" && len(addrs) == 0 {
+		return "", errors.New("no machine identifier available")
+	}
+	slices.Sort(addrs)
+	return "host:" + hostname + "|" + strings.Join(slices.Compact(addrs), ","), nil
+}
+
+// ActivationRequest describes an installation to the vendor so an activation
+// code can be issued offline. Encode it to a single line for copy and paste.
+type ActivationRequest struct {
+	Fingerprint    string `json:"fingerprint"`
+	Hostname       string `json:"hostname,omitempty"`
+	ProductCode    string `json:"product_code,omitempty"`
+	ProductVersion string `json:"product_version,omitempty"`
+	// LicenseCode is the currently loaded license, set when renewing.
+	LicenseCode string    `json:"license_code,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ActivationRequest builds a request for this machine from the client's
+// product configuration and currently loaded license.
+func (m *Client) ActivationRequest() (*ActivationRequest, error) {
+	fingerprint, err := Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	r := &ActivationRequest{
+		Fingerprint:    fingerprint,
+		Hostname:       hostname,
+		ProductCode:    m.config.ProductCode,
+		ProductVersion: m.config.ProductVersion,
+		CreatedAt:      m.now().UTC().Truncate(time.Second),
+	}
+	if license := m.getCurrentLicense(); license != nil && !license.Trial {
+		r.LicenseCode = license.LicenseCode
+	}
+	return r, nil
+}
+
+// Encode returns the request as unpadded base64url JSON.
+func (r *ActivationRequest) Encode() (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeActivationRequest parses the output of ActivationRequest.Encode.
+func DecodeActivationRequest(s string) (*ActivationRequest, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, errors.New("invalid activation request: " + err.Error())
+	}
+	var r ActivationRequest
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return nil, errors.New("invalid activation request: " + err.Error())
+	}
+	if r.Fingerprint == "" {
+		return nil, errors.New("invalid activation request: missing fingerprint")
+	}
+	return &r, nil
+}
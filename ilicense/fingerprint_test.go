@@ -0,0 +1,44 @@
+package ilicense
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFingerprintIsStable(t *testing.T) {
+	first, err := Fingerprint()
+	if err != nil {
+		t.Skipf("no machine identifier in this environment: %v", err)
+	}
+	second, _ := Fingerprint()
+	if len(first) != 64 || first != second {
+		t.Fatalf("expected a stable hex digest, got %q and %q", first, second)
+	}
+}
+
+func TestActivationRequestRoundTrip(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProductCode = "p-a"
+	cfg.ProductVersion = "2.1.0"
+	client := NewClient(&cfg)
+	client.setCurrentLicense(&License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})
+
+	req, err := client.ActivationRequest()
+	if err != nil {
+		t.Skipf("no machine identifier in this environment: %v", err)
+	}
+	encoded, err := req.Encode()
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	got, err := DecodeActivationRequest(encoded)
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if *got != *req || got.ProductCode != "p-a" || got.LicenseCode != "L1" {
+		t.Fatalf("unexpected round trip: %+v != %+v", got, req)
+	}
+	if _, err := DecodeActivationRequest("bm90LWpzb24"); err == nil {
+		t.Fatalf("expected error for invalid request")
+	}
+}
@@ -0,0 +1,128 @@
+package ilicense
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrUnhealthy means the license state is classified as unhealthy by the HealthPolicy.
+var ErrUnhealthy = errors.New("license unhealthy")
+
+// HealthState is the outcome of a health check, using the pass/warn/fail
+// vocabulary of common health-check aggregators.
+type HealthState string
+
+const (
+	HealthPass HealthState = "pass"
+	HealthWarn HealthState = "warn"
+	HealthFail HealthState = "fail"
+)
+
+// HealthPolicy classifies license statuses for health checks.
+// The zero value reports expired licenses as unhealthy, trials and
+// not-activated installs as degraded and everything else as healthy, so a
+// fresh install stays ready and can reach its activation endpoint.
+type HealthPolicy struct {
+	// Degraded lists statuses reported as HealthWarn; nil means trial and
+	// not_activated.
+	Degraded []LicenseStatus `json:"degraded"`
+	// Unhealthy lists statuses reported as HealthFail; nil means expired.
+	// Unhealthy wins over Degraded.
+	Unhealthy []LicenseStatus `json:"unhealthy"`
+	// ExpiryWarning reports a healthy license as degraded when it expires
+	// within this window; 0 disables the warning.
+	ExpiryWarning time.Duration `json:"expiry_warning"`
+	// ExpiredGrace keeps an unhealthy expired license degraded until
+	// ExpireAt+ExpiredGrace, so readiness only fails once the grace period
+	// has passed; 0 fails immediately.
+	ExpiredGrace time.Duration `json:"expired_grace"`
+}
+
+// HealthReport describes the license state at the time of a health check.
+type HealthReport struct {
+	State  HealthState
+	Status LicenseStatus
+	// Err is the status error from Status, if any.
+	Err      error
+	ExpireAt time.Time
+	// DaysLeft counts whole days from Time to ExpireAt; it is negative once
+	// expired and 0 for perpetual licenses.
+	DaysLeft int64
+	Time     time.Time
+}
+
+// HealthError is returned by HealthCheck for unhealthy license states.
+type HealthError struct {
+	Status LicenseStatus
+	Err    error
+}
+
+func (e *HealthError) Error() string {
+	msg := ErrUnhealthy.Error() + ": status " + string(e.Status)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is reports ErrUnhealthy in addition to the wrapped status error.
+func (e *HealthError) Is(target error) bool { return target == ErrUnhealthy }
+
+func (e *HealthError) Unwrap() error { return e.Err }
+
+// Health classifies the current license state with Config.Health. Like
+// Status it neither logs nor audits, so it can back frequent probes.
+func (m *Client) Health(ctx context.Context) HealthReport {
+	now := m.now()
+	status, err := m.Status()
+	report := HealthReport{Status: status, Err: err, Time: now}
+	if s := m.loadState(now); s != nil && s.license != nil {
+		report.ExpireAt = s.license.ExpireAt
+		report.DaysLeft = daysLeft(report.ExpireAt, now)
+	}
+	report.State = m.config.Health.classify(status, report.ExpireAt, now)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		report.State = HealthFail
+		report.Err = ctxErr
+	}
+	return report
+}
+
+// HealthCheck returns nil when the license is healthy or degraded, and a
+// *HealthError matching ErrUnhealthy otherwise. It suits readiness probes.
+func (m *Client) HealthCheck(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	report := m.Health(ctx)
+	if report.State != HealthFail {
+		return nil
+	}
+	return &HealthError{Status: report.Status, Err: report.Err}
+}
+
+func (p HealthPolicy) classify(status LicenseStatus, expireAt, now time.Time) HealthState {
+	unhealthy := p.Unhealthy
+	if unhealthy == nil {
+		unhealthy = []LicenseStatus{LicenseStatusExpired}
+	}
+	degraded := p.Degraded
+	if degraded == nil {
+		degraded = []LicenseStatus{LicenseStatusTrial, LicenseStatusNotActivated}
+	}
+	switch {
+	case slices.Contains(unhealthy, status):
+		if status == LicenseStatusExpired && p.ExpiredGrace > 0 && !expireAt.IsZero() && !now.After(expireAt.Add(p.ExpiredGrace)) {
+			return HealthWarn
+		}
+		return HealthFail
+	case slices.Contains(degraded, status):
+		return HealthWarn
+	case p.ExpiryWarning > 0 && !expireAt.IsZero() && expireAt.Sub(now) < p.ExpiryWarning:
+		return HealthWarn
+	default:
+		return HealthPass
+	}
+}
@@ -0,0 +1,117 @@
+package ilicense
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckDefaultPolicy(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(time.Hour)})
+	if err := client.HealthCheck(ctx); err != nil {
+		t.Fatalf("expected valid license to be healthy, got %v", err)
+	}
+
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(-time.Minute)})
+	err := client.HealthCheck(ctx)
+	if !errors.Is(err, ErrUnhealthy) || !errors.Is(err, ErrLicenseExpired) {
+		t.Fatalf("expected unhealthy expired error, got %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := client.HealthCheck(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context error, got %v", err)
+	}
+}
+
+func TestHealthPolicyOverrides(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Health.Unhealthy = []LicenseStatus{LicenseStatusNotActivated}
+	cfg.Health.Degraded = []LicenseStatus{LicenseStatusExpired}
+	client := NewClient(&cfg)
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(-time.Minute)})
+
+	report := client.Health(context.Background())
+	if report.State != HealthWarn || report.Status != LicenseStatusExpired {
+		t.Fatalf("expected expired license to be degraded, got %+v", report)
+	}
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("degraded state must not fail HealthCheck, got %v", err)
+	}
+}
+
+func TestHealthExpiryWarning(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Health.ExpiryWarning = 30 * 24 * time.Hour
+	client := NewClient(&cfg)
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(7 * 24 * time.Hour)})
+	if state := client.Health(context.Background()).State; state != HealthWarn {
+		t.Fatalf("expected expiring license to be degraded, got %q", state)
+	}
+}
+
+func TestHealthDaysLeftFollowsClock(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	cfg := DefaultConfig()
+	cfg.Clock = clock
+	client := NewClient(&cfg)
+	client.setEntries([]storedLicense{{code: "c", license: &License{LicenseCode: "L1", ExpireAt: clock.now.Add(365*24*time.Hour + time.Hour)}}})
+	if got := client.Health(context.Background()).DaysLeft; got != 365 {
+		t.Fatalf("expected 365 days left, got %d", got)
+	}
+
+	clock.now = clock.now.Add(48 * time.Hour)
+	if got := client.Health(context.Background()).DaysLeft; got != 363 {
+		t.Fatalf("expected 363 days left after 48h, got %d", got)
+	}
+
+	clock.now = clock.now.Add(400 * 24 * time.Hour)
+	report := client.Health(context.Background())
+	if report.State != HealthFail || report.DaysLeft >= 0 {
+		t.Fatalf("expected failing report with negative days left, got %+v", report)
+	}
+}
+
+func TestHealthNotActivatedIsDegraded(t *testing.T) {
+	client := NewClient(nil)
+	report := client.Health(context.Background())
+	if report.State != HealthWarn || report.Status != LicenseStatusNotActivated {
+		t.Fatalf("expected a fresh install to be degraded, got %+v", report)
+	}
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("a fresh install must stay ready, got %v", err)
+	}
+}
+
+func TestHealthExpiredGrace(t *testing.T) {
+	expireAt := time.Now().Add(-time.Hour)
+	clock := &testClock{}
+	cfg := DefaultConfig()
+	cfg.Clock = clock
+	cfg.Health.ExpiredGrace = 72 * time.Hour
+	client := NewClient(&cfg)
+	client.setCurrentLicense(&License{LicenseCode: "L1", ExpireAt: expireAt})
+
+	for _, tc := range []struct {
+		now  time.Time
+		want HealthState
+	}{
+		{expireAt.Add(time.Minute), HealthWarn},
+		{expireAt.Add(72*time.Hour - time.Nanosecond), HealthWarn},
+		{expireAt.Add(72 * time.Hour), HealthWarn},
+		{expireAt.Add(72*time.Hour + time.Nanosecond), HealthFail},
+	} {
+		clock.now = tc.now
+		report := client.Health(context.Background())
+		if report.State != tc.want || report.Status != LicenseStatusExpired {
+			t.Fatalf("at %v past expiry: expected %q, got %+v", tc.now.Sub(expireAt), tc.want, report)
+		}
+	}
+	if err := client.HealthCheck(context.Background()); !errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("expected HealthCheck to fail after the grace period, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package ilicense
+
+import "context"
+
+// Span names passed to Hooks.StartSpan.
+const (
+	SpanInit          = "ilicense.Init"
+	SpanActivate      = "ilicense.Activate"
+	SpanDeactivate    = "ilicense.Deactivate"
+	SpanStorageLoad   = "ilicense.storage.Load"
+	SpanStorageSave   = "ilicense.storage.Save"
+	SpanStorageRemove = "ilicense.storage.Remove"
+)
+
+// Hooks observe client events, e.g. for metrics and tracing. Nil funcs are
+// skipped. Hooks run synchronously on the calling goroutine and must not
+// block or call back into Activate or Deactivate.
+type Hooks struct {
+	// OnActivate is called after every activation attempt; err is nil on success.
+	OnActivate func(license *License, err error)
+	// OnValidationFailure is called when an activation code or the stored
+	// license fails validation.
+	OnValidationFailure func(err error)
+	// OnLicenseCheck is called after CheckLicense; err is nil when a valid license is loaded.
+	OnLicenseCheck func(err error)
+	// OnModuleCheck is called after CheckModule; err is nil when the module is granted.
+	OnModuleCheck func(module string, err error)
+	// StartSpan starts a span named one of the Span constants around Init,
+	// Activate, Deactivate and storage I/O. end is called with the operation's
+	// error when it finishes.
+	StartSpan func(ctx context.Context, name string) (_ context.Context, end func(err error))
+}
+
+// JoinHooks combines hooks so each event reaches all of them in order.
+// Spans are nested in the same order.
+func JoinHooks(hooks ...Hooks) Hooks {
+	var out Hooks
+	for _, h := range hooks {
+		out.OnActivate = joinFunc2(out.OnActivate, h.OnActivate)
+		out.OnValidationFailure = joinFunc1(out.OnValidationFailure, h.OnValidationFailure)
+		out.OnLicenseCheck = joinFunc1(out.OnLicenseCheck, h.OnLicenseCheck)
+		out.OnModuleCheck = joinFunc2(out.OnModuleCheck, h.OnModuleCheck)
+		out.StartSpan = joinStartSpan(out.StartSpan, h.StartSpan)
+	}
+	return out
+}
+
+func joinFunc1[A any](a, b func(A)) func(A) {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	return func(x A) { a(x); b(x) }
+}
+
+func joinFunc2[A, B any](a, b func(A, B)) func(A, B) {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	return func(x A, y B) { a(x, y); b(x, y) }
+}
+
+func joinStartSpan(a, b func(context.Context, string) (context.Context, func(error))) func(context.Context, string) (context.Context, func(error)) {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	return func(ctx context.Context, name string) (context.Context, func(error)) {
+		ctx, endA := a(ctx, name)
+		ctx, endB := b(ctx, name)
+		return ctx, func(err error) { endB(err); endA(err) }
+	}
+}
+
+func noopEnd(error) {}
+
+func (m *Client) startSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	if h := m.config.Hooks.StartSpan; h != nil {
+		return h(ctx, name)
+	}
+	return ctx, noopEnd
+}
+
+func (m *Client) hookActivate(license *License, err error) {
+	if h := m.config.Hooks.OnActivate; h != nil {
+		h(license, err)
+	}
+}
+
+func (m *Client) hookValidationFailure(err error) {
+	if h := m.config.Hooks.OnValidationFailure; h != nil {
+		h(err)
+	}
+}
+
+func (m *Client) hookLicenseCheck(err error) {
+	if h := m.config.Hooks.OnLicenseCheck; h != nil {
+		h(err)
+	}
+}
+
+func (m *Client) hookModuleCheck(module string, err error) {
+	if h := m.config.Hooks.OnModuleCheck; h != nil {
+		h(module, err)
+	}
+}
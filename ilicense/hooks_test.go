@@ -0,0 +1,79 @@
+package ilicense
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+func TestHooksObserveEvents(t *testing.T) {
+	var activations, failures []error
+	var denied []string
+	cfg := activationConfig(t)
+	cfg.Hooks = Hooks{
+		OnActivate:          func(_ *License, err error) { activations = append(activations, err) },
+		OnValidationFailure: func(err error) { failures = append(failures, err) },
+		OnModuleCheck: func(module string, err error) {
+			if err != nil {
+				denied = append(denied, module)
+			}
+		},
+	}
+	client := NewClient(&cfg)
+
+	expired := signTestLicense(t, licensing.License{LicenseCode: "L0", ExpireAt: time.Now().Add(-time.Hour)})
+	if _, err := client.Activate(expired); !errors.Is(err, ErrLicenseExpired) {
+		t.Fatalf("expected ErrLicenseExpired, got %v", err)
+	}
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour), Modules: "m-a"})
+	if _, err := client.Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+	_ = client.CheckModule("m-a")
+	_ = client.CheckModule("m-b")
+
+	if len(activations) != 2 || activations[0] == nil || activations[1] != nil {
+		t.Fatalf("unexpected activation events: %v", activations)
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], ErrLicenseExpired) {
+		t.Fatalf("unexpected validation failures: %v", failures)
+	}
+	if len(denied) != 1 || denied[0] != "m-b" {
+		t.Fatalf("unexpected denied modules: %v", denied)
+	}
+}
+
+func TestJoinHooksNestsSpans(t *testing.T) {
+	var events []string
+	tracer := func(tag string) Hooks {
+		return Hooks{
+			StartSpan: func(ctx context.Context, name string) (context.Context, func(error)) {
+				events = append(events, tag+" start "+name)
+				return ctx, func(error) { events = append(events, tag+" end "+name) }
+			},
+			OnLicenseCheck: func(error) { events = append(events, tag+" check") },
+		}
+	}
+	cfg := activationConfig(t)
+	cfg.Hooks = JoinHooks(tracer("a"), Hooks{}, tracer("b"))
+	client := NewClient(&cfg)
+	if err := client.Init(); !errors.Is(err, ErrLicenseNotFound) {
+		t.Fatalf("expected ErrLicenseNotFound, got %v", err)
+	}
+	_ = client.CheckLicense()
+
+	want := []string{
+		"a start " + SpanInit, "b start " + SpanInit,
+		"a start " + SpanStorageLoad, "b start " + SpanStorageLoad,
+		"b end " + SpanStorageLoad, "a end " + SpanStorageLoad,
+		"b end " + SpanInit, "a end " + SpanInit,
+		"a check", "b check",
+	}
+	if strings.Join(events, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected events:\n got %v\nwant %v", events, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Package httpadmin provides an http.Handler for product web consoles to show
+// the license status and to activate, preview or remove activation codes.
+//
+// Routes, relative to where the handler is mounted:
+//
+//	GET    /license           current status, effective license and stored licenses
+//	POST   /license/activate  activate a code
+//	POST   /license/preview   preview a code without applying it
+//	DELETE /license           deactivate
+//
+// POST bodies are either JSON {"activation_code": "...", "force": false} or the
+// raw code as text/plain. Errors are returned as {"error": {"code", "message"}}.
+package httpadmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+// maxBodyBytes bounds activation request bodies.
+const maxBodyBytes = 64 << 10
+
+// Manager is the client API used by the handler; *ilicense.Client implements it.
+type Manager interface {
+	CheckLicenseStatus() (ilicense.LicenseStatus, error)
+	GetCurrentLicense() *ilicense.License
+	Licenses() []*ilicense.License
+	ActivateWithOptionsContext(ctx context.Context, code string, opts ilicense.ActivateOptions) (*ilicense.License, error)
+	PreviewActivation(code string) (*ilicense.ActivationPreview, error)
+	DeactivateContext(ctx context.Context) error
+}
+
+var _ Manager = (*ilicense.Client)(nil)
+
+// Options configures the handler.
+type Options struct {
+	// Authorize is called before every request; a non-nil error rejects it
+	// with 403. It is required: with a nil Authorize every request is
+	// rejected, so deployments without their own access control must opt in
+	// explicitly, e.g. with func(*http.Request) error { return nil }.
+	Authorize func(r *http.Request) error
+}
+
+// StatusResponse is the body of GET /license.
+type StatusResponse struct {
+	Status   ilicense.LicenseStatus `json:"status"`
+	License  *ilicense.License      `json:"license,omitempty"`
+	Licenses []*ilicense.License    `json:"licenses,omitempty"`
+	Error    *ErrorBody             `json:"error,omitempty"`
+}
+
+// ActivateResponse is the body of POST /license/activate.
+type ActivateResponse struct {
+	Status  ilicense.LicenseStatus `json:"status"`
+	License *ilicense.License      `json:"license"`
+}
+
+// ErrorBody describes a failed request.
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type activateRequest struct {
+	ActivationCode string `json:"activation_code"`
+	Force          bool   `json:"force"`
+}
+
+type handler struct {
+	manager Manager
+	opts    Options
+	mux     *http.ServeMux
+}
+
+// errNoAuthorize rejects requests to a handler built without Options.Authorize.
+var errNoAuthorize = errors.New("admin handler has no Authorize configured")
+
+// NewHandler returns the admin handler for manager. It fails closed: without
+// Options.Authorize every request is rejected with 403.
+func NewHandler(manager Manager, opts Options) http.Handler {
+	h := &handler{manager: manager, opts: opts, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /license", h.status)
+	h.mux.HandleFunc("POST /license/activate", h.activate)
+	h.mux.HandleFunc("POST /license/preview", h.preview)
+	h.mux.HandleFunc("DELETE /license", h.deactivate)
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := errNoAuthorize
+	if h.opts.Authorize != nil {
+		err = h.opts.Authorize(r)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusForbidden, errorResponse{Error: ErrorBody{Code: "forbidden", Message: err.Error()}})
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) status(w http.ResponseWriter, r *http.Request) {
+	status, err := h.manager.CheckLicenseStatus()
+	resp := StatusResponse{Status: status, License: h.manager.GetCurrentLicense()}
+	if licenses := h.manager.Licenses(); len(licenses) > 1 {
+		resp.Licenses = licenses
+	}
+	if err != nil {
+		_, body := ErrorStatus(err)
+		resp.Error = &body
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *handler) activate(w http.ResponseWriter, r *http.Request) {
+	req, ok := readActivateRequest(w, r)
+	if !ok {
+		return
+	}
+	license, err := h.manager.ActivateWithOptionsContext(r.Context(), req.ActivationCode, ilicense.ActivateOptions{Force: req.Force})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	status, _ := h.manager.CheckLicenseStatus()
+	writeJSON(w, http.StatusOK, ActivateResponse{Status: status, License: license})
+}
+
+func (h *handler) preview(w http.ResponseWriter, r *http.Request) {
+	req, ok := readActivateRequest(w, r)
+	if !ok {
+		return
+	}
+	preview, err := h.manager.PreviewActivation(req.ActivationCode)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, preview)
+}
+
+func (h *handler) deactivate(w http.ResponseWriter, r *http.Request) {
+	if err := h.manager.DeactivateContext(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func readActivateRequest(w http.ResponseWriter, r *http.Request) (activateRequest, bool) {
+	var req activateRequest
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: ErrorBody{Code: "bad_request", Message: err.Error()}})
+		return req, false
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(data, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: ErrorBody{Code: "bad_request", Message: "invalid JSON body: " + err.Error()}})
+			return req, false
+		}
+	} else {
+		req.ActivationCode = string(data)
+	}
+	if strings.TrimSpace(req.ActivationCode) == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: ErrorBody{Code: "bad_request", Message: "activation_code is required"}})
+		return req, false
+	}
+	return req, true
+}
+
+// ErrorStatus maps an SDK error to an HTTP status code and an error body
+// whose Code is ilicense.ErrorCode(err).
+func ErrorStatus(err error) (int, ErrorBody) {
+	code := ilicense.ErrorCode(err)
+	body := ErrorBody{Code: code, Message: err.Error()}
+	switch code {
+	case ilicense.CodeLicenseNotFound:
+		return http.StatusNotFound, body
+	case ilicense.CodeModuleUnauthorized:
+		return http.StatusForbidden, body
+	case ilicense.CodeLicenseDowngrade, ilicense.CodeAddOnWithoutBase, ilicense.CodeTrialTampered:
+		return http.StatusConflict, body
+	case ilicense.CodeCanceled, ilicense.CodeDeadlineExceeded:
+		return http.StatusServiceUnavailable, body
+	case ilicense.CodeInvalidPublicKey, ilicense.CodeUnsupportedAlgorithm,
+		ilicense.CodeInvalidConfig, ilicense.CodeStorageError, ilicense.CodeAuditTampered, ilicense.CodeUnknown:
+		// Server-side configuration or I/O problems, not a bad activation code.
+		return http.StatusInternalServerError, body
+	default:
+		return http.StatusUnprocessableEntity, body
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, body := ErrorStatus(err)
+	writeJSON(w, status, errorResponse{Error: body})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,109 @@
+package httpadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/ilicensetest"
+)
+
+var allowAll = Options{Authorize: func(*http.Request) error { return nil }}
+
+func do(h http.Handler, method, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestActivateStatusDeactivate(t *testing.T) {
+	client := ilicensetest.NewClient(t, nil)
+	h := NewHandler(client, allowAll)
+
+	var status StatusResponse
+	rec := do(h, http.MethodGet, "/license", "", "")
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil || status.Status != ilicense.LicenseStatusNotActivated || status.Error.Code != "license_not_found" {
+		t.Fatalf("unexpected initial status %d %+v %v", rec.Code, status, err)
+	}
+
+	code := ilicensetest.Sign(t, ilicensetest.NewLicense(ilicensetest.WithModules("m-a")))
+	rec = do(h, http.MethodPost, "/license/preview", "text/plain", code)
+	var preview ilicense.ActivationPreview
+	if err := json.NewDecoder(rec.Body).Decode(&preview); err != nil || rec.Code != http.StatusOK || len(preview.Diff.ModulesAdded) != 1 {
+		t.Fatalf("unexpected preview %d %+v %v", rec.Code, preview, err)
+	}
+	if client.IsValid() {
+		t.Fatalf("preview must not activate")
+	}
+
+	body, _ := json.Marshal(map[string]any{"activation_code": code})
+	rec = do(h, http.MethodPost, "/license/activate", "application/json", string(body))
+	if rec.Code != http.StatusOK || !client.HasModule("m-a") {
+		t.Fatalf("unexpected activate response %d %s", rec.Code, rec.Body)
+	}
+
+	if rec = do(h, http.MethodDelete, "/license", "", ""); rec.Code != http.StatusNoContent || client.IsValid() {
+		t.Fatalf("unexpected deactivate response %d", rec.Code)
+	}
+}
+
+func TestActivateErrors(t *testing.T) {
+	h := NewHandler(ilicensetest.NewClient(t, nil), allowAll)
+
+	expired := ilicensetest.Sign(t, ilicensetest.NewLicense(ilicensetest.ExpiringIn(-time.Hour)))
+	cases := []struct {
+		body   string
+		status int
+		code   string
+	}{
+		{"", http.StatusBadRequest, "bad_request"},
+		{"not-a-code", http.StatusUnprocessableEntity, ilicense.CodeTruncatedPayload},
+		{expired, http.StatusUnprocessableEntity, "license_expired"},
+	}
+	for _, tc := range cases {
+		rec := do(h, http.MethodPost, "/license/activate", "text/plain", tc.body)
+		var resp errorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != tc.status || resp.Error.Code != tc.code {
+			t.Fatalf("body %q: expected %d %s, got %d %+v", tc.body, tc.status, tc.code, rec.Code, resp)
+		}
+	}
+}
+
+func TestAuthorizeHook(t *testing.T) {
+	h := NewHandler(ilicensetest.NewClient(t, nil), Options{Authorize: func(r *http.Request) error {
+		if r.Header.Get("X-Admin") == "" {
+			return errors.New("admin required")
+		}
+		return nil
+	}})
+	if rec := do(h, http.MethodDelete, "/license", "", ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected unauthorized request to be rejected, got %d", rec.Code)
+	}
+
+	client := ilicensetest.NewClient(t, nil, ilicensetest.NewLicense())
+	unset := NewHandler(client, Options{})
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if rec := do(unset, method, "/license", "", ""); rec.Code != http.StatusForbidden {
+			t.Fatalf("expected %s without Authorize to be rejected, got %d", method, rec.Code)
+		}
+	}
+	if client.GetCurrentLicense() == nil {
+		t.Fatalf("expected the license to survive a rejected DELETE")
+	}
+}
+
+func TestErrorStatusDowngrade(t *testing.T) {
+	status, body := ErrorStatus(&ilicense.DowngradeError{})
+	if status != http.StatusConflict || body.Code != "license_downgrade" {
+		t.Fatalf("unexpected mapping %d %+v", status, body)
+	}
+}
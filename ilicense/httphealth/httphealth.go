@@ -0,0 +1,82 @@
+// Package httphealth exposes license health as an http.Handler for readiness
+// probes and health-check aggregators.
+//
+// Responses use the application/health+json format (draft-inadarei-api-health-check):
+//
+//	{"status":"warn","checks":{"license:status":[{"componentType":"component",
+//	 "observedValue":"trial","status":"warn","time":"2026-01-02T15:04:05Z"}]}}
+//
+// Healthy and degraded states answer 200; unhealthy answers 503.
+package httphealth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+// Checker reports license health; *ilicense.Client implements it.
+type Checker interface {
+	Health(ctx context.Context) ilicense.HealthReport
+}
+
+var _ Checker = (*ilicense.Client)(nil)
+
+// Response is the health+json body.
+type Response struct {
+	Status ilicense.HealthState `json:"status"`
+	Output string               `json:"output,omitempty"`
+	Checks map[string][]Check   `json:"checks"`
+}
+
+// Check is one entry of Response.Checks.
+type Check struct {
+	ComponentType string               `json:"componentType"`
+	ObservedValue any                  `json:"observedValue"`
+	ObservedUnit  string               `json:"observedUnit,omitempty"`
+	Status        ilicense.HealthState `json:"status"`
+	Output        string               `json:"output,omitempty"`
+	Time          time.Time            `json:"time"`
+}
+
+// NewHandler returns a handler reporting the health of checker.
+func NewHandler(checker Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Health(r.Context())
+		resp := Response{
+			Status: report.State,
+			Checks: map[string][]Check{
+				"license:status": {{
+					ComponentType: "component",
+					ObservedValue: report.Status,
+					Status:        report.State,
+					Time:          report.Time,
+				}},
+			},
+		}
+		if report.Err != nil {
+			resp.Output = report.Err.Error()
+			resp.Checks["license:status"][0].Output = resp.Output
+		}
+		if !report.ExpireAt.IsZero() {
+			resp.Checks["license:daysLeft"] = []Check{{
+				ComponentType: "component",
+				ObservedValue: report.DaysLeft,
+				ObservedUnit:  "d",
+				Status:        report.State,
+				Time:          report.Time,
+			}}
+		}
+		code := http.StatusOK
+		if report.State == ilicense.HealthFail {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/health+json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
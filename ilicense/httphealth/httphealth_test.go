@@ -0,0 +1,54 @@
+package httphealth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/ilicensetest"
+)
+
+func serve(t *testing.T, client *ilicense.Client) (int, Response) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	NewHandler(client).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var resp Response
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/health+json" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	return rec.Code, resp
+}
+
+func TestHandlerStates(t *testing.T) {
+	valid := ilicensetest.NewClient(t, nil, ilicensetest.NewLicense())
+	if code, resp := serve(t, valid); code != http.StatusOK || resp.Status != ilicense.HealthPass || len(resp.Checks["license:daysLeft"]) != 1 {
+		t.Fatalf("unexpected valid response %d %+v", code, resp)
+	}
+
+	cfg := ilicense.DefaultConfig()
+	cfg.Health.ExpiryWarning = 30 * 24 * time.Hour
+	expiring := ilicensetest.NewClient(t, &cfg, ilicensetest.NewLicense(ilicensetest.ExpiringIn(7*24*time.Hour)))
+	if code, resp := serve(t, expiring); code != http.StatusOK || resp.Status != ilicense.HealthWarn {
+		t.Fatalf("unexpected expiring response %d %+v", code, resp)
+	}
+
+	missing := ilicensetest.NewClient(t, nil)
+	if code, resp := serve(t, missing); code != http.StatusOK || resp.Status != ilicense.HealthWarn || resp.Output == "" {
+		t.Fatalf("unexpected not-activated response %d %+v", code, resp)
+	}
+
+	expired := ilicensetest.NewClient(t, nil, ilicensetest.NewLicense(ilicensetest.ExpiringIn(-48*time.Hour)))
+	code, resp := serve(t, expired)
+	if code != http.StatusServiceUnavailable || resp.Status != ilicense.HealthFail {
+		t.Fatalf("unexpected expired response %d %+v", code, resp)
+	}
+	if days := resp.Checks["license:daysLeft"]; len(days) != 1 || days[0].ObservedValue != float64(-2) {
+		t.Fatalf("expected daysLeft -2 for a license expired two days ago, got %+v", days)
+	}
+}
@@ -0,0 +1,157 @@
+// Package httpmw provides net/http middleware that gates handlers on the
+// license and module checks of an ilicense.Checker. Allowed requests carry the
+// current license, available to handlers through ilicense.FromContext.
+//
+// Denied requests receive an RFC 7807 problem-details JSON body by default:
+//
+//	{"type":"about:blank","title":"Forbidden","status":403,
+//	 "detail":"unauthorized module: m-a","license_status":"valid","module":"m-a"}
+package httpmw
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+// Options configures a Middleware.
+type Options struct {
+	// StatusCode is used for denied requests; 0 uses http.StatusForbidden.
+	StatusCode int
+	// Skip lets matching requests bypass all checks, e.g. health endpoints.
+	Skip func(r *http.Request) bool
+	// ErrorHandler replaces the default problem-details response.
+	// module is empty for license-only checks.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, module string, err error)
+}
+
+// Problem is the problem-details body written for denied requests.
+type Problem struct {
+	Type          string                 `json:"type"`
+	Title         string                 `json:"title"`
+	Status        int                    `json:"status"`
+	Detail        string                 `json:"detail,omitempty"`
+	LicenseStatus ilicense.LicenseStatus `json:"license_status"`
+	Module        string                 `json:"module,omitempty"`
+}
+
+// Middleware gates handlers with a Checker.
+type Middleware struct {
+	checker ilicense.Checker
+	opts    Options
+}
+
+// New creates middleware for checker.
+func New(checker ilicense.Checker, opts Options) *Middleware {
+	return &Middleware{checker: checker, opts: opts}
+}
+
+// RequireLicense rejects requests unless checker holds a valid license.
+func RequireLicense(checker ilicense.Checker) func(http.Handler) http.Handler {
+	return New(checker, Options{}).RequireLicense
+}
+
+// RequireModule rejects requests unless checker grants module.
+func RequireModule(checker ilicense.Checker, module string) func(http.Handler) http.Handler {
+	return New(checker, Options{}).RequireModule(module)
+}
+
+// RequireLicense rejects requests unless a valid license is loaded.
+func (m *Middleware) RequireLicense(next http.Handler) http.Handler {
+	return m.guard(next, func(*http.Request) string { return "" })
+}
+
+// RequireModule returns middleware that rejects requests unless module is granted.
+func (m *Middleware) RequireModule(module string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return m.guard(next, func(*http.Request) string { return module })
+	}
+}
+
+// RequireRoutes maps URL path prefixes to required modules; the longest
+// matching prefix wins. Prefixes match whole path segments, so "/admin" covers
+// "/admin" and "/admin/users" but not "/administrator". Paths matching no
+// prefix only require a valid license.
+func (m *Middleware) RequireRoutes(routes map[string]string) func(http.Handler) http.Handler {
+	prefixes := make([]string, 0, len(routes))
+	for p := range routes {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	resolve := func(r *http.Request) string {
+		for _, p := range prefixes {
+			if matchPrefix(r.URL.Path, p) {
+				return routes[p]
+			}
+		}
+		return ""
+	}
+	return func(next http.Handler) http.Handler {
+		return m.guard(next, resolve)
+	}
+}
+
+func matchPrefix(path, prefix string) bool {
+	if strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func (m *Middleware) guard(next http.Handler, moduleFor func(*http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.opts.Skip != nil && m.opts.Skip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		module := moduleFor(r)
+		ctx, err := ilicense.Guard(r.Context(), m.checker, module)
+		if err != nil {
+			m.deny(w, r, module, err)
+			return
+		}
+		if ctx != r.Context() {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *Middleware) deny(w http.ResponseWriter, r *http.Request, module string, err error) {
+	if m.opts.ErrorHandler != nil {
+		m.opts.ErrorHandler(w, r, module, err)
+		return
+	}
+	code := m.opts.StatusCode
+	if code == 0 {
+		code = http.StatusForbidden
+	}
+	status, _ := ilicense.StatusOf(m.checker)
+	var moduleErr *ilicense.ModuleUnauthorizedError
+	if errors.As(err, &moduleErr) {
+		module = moduleErr.Module
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:          "about:blank",
+		Title:         http.StatusText(code),
+		Status:        code,
+		Detail:        err.Error(),
+		LicenseStatus: status,
+		Module:        module,
+	})
+}
+
+// SkipPaths returns a Skip func bypassing requests whose path equals one of paths.
+func SkipPaths(paths ...string) func(*http.Request) bool {
+	set := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		set[p] = true
+	}
+	return func(r *http.Request) bool { return set[r.URL.Path] }
+}
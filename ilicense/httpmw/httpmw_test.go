@@ -0,0 +1,109 @@
+package httpmw
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+func serve(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestRequireModule(t *testing.T) {
+	checker := ilicense.Static(&ilicense.License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-a"})
+
+	if rec := serve(RequireModule(checker, "m-a")(okHandler), "/"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected granted module to pass, got %d", rec.Code)
+	}
+
+	rec := serve(RequireModule(checker, "m-b")(okHandler), "/")
+	if rec.Code != http.StatusForbidden || rec.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("expected problem response, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var p Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if p.Status != http.StatusForbidden || p.Module != "m-b" || p.LicenseStatus != ilicense.LicenseStatusValid {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+}
+
+func TestRequireRoutesAndSkip(t *testing.T) {
+	checker := ilicense.Static(&ilicense.License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-report"})
+	mw := New(checker, Options{StatusCode: http.StatusPaymentRequired, Skip: SkipPaths("/healthz")})
+	h := mw.RequireRoutes(map[string]string{
+		"/api/reports":        "m-report",
+		"/api/reports/export": "m-export",
+	})(okHandler)
+
+	cases := map[string]int{
+		"/api/reports/daily":      http.StatusNoContent,
+		"/api/reports/export/csv": http.StatusPaymentRequired,
+		"/other":                  http.StatusNoContent,
+		"/api/reports":            http.StatusNoContent,
+		"/api/reports/exporter":   http.StatusNoContent,
+		"/api/reports/export":     http.StatusPaymentRequired,
+	}
+	for path, want := range cases {
+		if rec := serve(h, path); rec.Code != want {
+			t.Fatalf("%s: expected %d, got %d", path, want, rec.Code)
+		}
+	}
+
+	denied := New(ilicense.DenyAll(), Options{Skip: SkipPaths("/healthz")}).RequireLicense(okHandler)
+	if rec := serve(denied, "/healthz"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected health endpoint to bypass check, got %d", rec.Code)
+	}
+	if rec := serve(denied, "/api"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected unlicensed request to be denied, got %d", rec.Code)
+	}
+}
+
+func TestRequestCarriesLicense(t *testing.T) {
+	checker := ilicense.Static(&ilicense.License{LicenseCode: "L1", ExpireAt: time.Now().Add(time.Hour)})
+	var got *ilicense.License
+	h := RequireLicense(checker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ilicense.FromContext(r.Context())
+	}))
+	serve(h, "/")
+	if got == nil || got.LicenseCode != "L1" {
+		t.Fatalf("expected license in request context, got %+v", got)
+	}
+}
+
+// statusChecker records whether the side-effecting CheckLicenseStatus is used.
+type statusChecker struct {
+	ilicense.Checker
+	checked bool
+}
+
+func (c *statusChecker) CheckLicenseStatus() (ilicense.LicenseStatus, error) {
+	c.checked = true
+	return c.Checker.CheckLicenseStatus()
+}
+
+func (c *statusChecker) Status() (ilicense.LicenseStatus, error) {
+	return c.Checker.CheckLicenseStatus()
+}
+
+func TestDenyUsesSideEffectFreeStatus(t *testing.T) {
+	checker := &statusChecker{Checker: ilicense.Static(&ilicense.License{ExpireAt: time.Now().Add(-time.Hour)})}
+	rec := serve(RequireLicense(checker)(okHandler), "/")
+	var p Problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil || p.LicenseStatus != ilicense.LicenseStatusExpired {
+		t.Fatalf("unexpected problem: %+v %v", p, err)
+	}
+	if checker.checked {
+		t.Fatalf("deny must not call CheckLicenseStatus, which logs and audits")
+	}
+}
@@ -0,0 +1,268 @@
+// Package ilicensetest provides helpers for testing applications that gate
+// features with ilicense: a shared in-memory test issuer, license builders,
+// a fake clock, in-memory storage and a Client preloaded with licenses.
+//
+// Licenses are signed and verified through the regular activation path, so
+// tests exercise the same code as production without touching the filesystem.
+package ilicensetest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	"github.com/xbingbo/ilicense-client-go/ilicense/issuer"
+)
+
+var testIssuer = sync.OnceValues(issuer.Generate)
+
+// Issuer returns the process-wide test issuer. Its key is generated on first
+// use and never leaves memory.
+func Issuer() *issuer.Issuer {
+	iss, err := testIssuer()
+	if err != nil {
+		panic("ilicensetest: generate test key: " + err.Error())
+	}
+	return iss
+}
+
+// PublicKey returns the public key of the test issuer.
+func PublicKey() string {
+	return Issuer().PublicKey()
+}
+
+// Sign signs license with the test issuer.
+func Sign(t testing.TB, license *ilicense.License) string {
+	t.Helper()
+	code, err := Issuer().Sign(license)
+	if err != nil {
+		t.Fatalf("ilicensetest: sign license: %v", err)
+	}
+	return code
+}
+
+// Option customizes a license built by NewLicense.
+type Option func(*builder)
+
+type builder struct {
+	license   ilicense.License
+	expiresIn *time.Duration
+}
+
+// NewLicense builds a license issued now and valid for one year. Options are
+// applied in order; ExpiringIn is resolved relative to the issue time.
+func NewLicense(opts ...Option) *ilicense.License {
+	year := 365 * 24 * time.Hour
+	b := &builder{
+		license: ilicense.License{
+			LicenseCode:  "test-license",
+			CustomerCode: "test-customer",
+			CustomerName: "Test Customer",
+			IssuerCode:   "test-issuer",
+			IssuerName:   "ilicensetest",
+			IssueAt:      time.Now().UTC().Truncate(time.Second),
+		},
+		expiresIn: &year,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.expiresIn != nil {
+		b.license.ExpireAt = b.license.IssueAt.Add(*b.expiresIn)
+	}
+	return &b.license
+}
+
+// WithModules grants modules.
+func WithModules(modules ...string) Option {
+	return func(b *builder) {
+		existing := b.license.Modules
+		if existing != "" {
+			existing += ","
+		}
+		b.license.Modules = existing + strings.Join(modules, ",")
+	}
+}
+
+// ExpiringIn sets ExpireAt to the issue time plus d; negative values build an expired license.
+func ExpiringIn(d time.Duration) Option {
+	return func(b *builder) { b.expiresIn = &d }
+}
+
+// Perpetual builds a license without expiry.
+func Perpetual() Option {
+	return func(b *builder) { b.expiresIn = nil; b.license.ExpireAt = time.Time{} }
+}
+
+// IssuedAt sets the issue time, e.g. to match a FakeClock.
+func IssuedAt(t time.Time) Option {
+	return func(b *builder) { b.license.IssueAt = t }
+}
+
+// WithLimit sets a named numeric limit.
+func WithLimit(name string, value int64) Option {
+	return func(b *builder) {
+		if b.license.Limits == nil {
+			b.license.Limits = make(map[string]int64)
+		}
+		b.license.Limits[name] = value
+	}
+}
+
+// WithMaxInstances sets MaxInstances.
+func WithMaxInstances(n int) Option {
+	return func(b *builder) { b.license.MaxInstances = n }
+}
+
+// WithClaim sets a custom claim.
+func WithClaim(key string, value any) Option {
+	return func(b *builder) {
+		if b.license.Claims == nil {
+			b.license.Claims = make(map[string]any)
+		}
+		b.license.Claims[key] = value
+	}
+}
+
+// WithEdition sets the edition name.
+func WithEdition(edition string) Option {
+	return func(b *builder) { b.license.Edition = edition }
+}
+
+// WithProduct sets the product code and allowed version range.
+func WithProduct(code, versions string) Option {
+	return func(b *builder) {
+		b.license.ProductCode = code
+		b.license.ProductVersions = versions
+	}
+}
+
+// WithCode sets LicenseCode and Revision.
+func WithCode(code string, revision int) Option {
+	return func(b *builder) {
+		b.license.LicenseCode = code
+		b.license.Revision = revision
+	}
+}
+
+// AsAddOn marks the license as an add-on.
+func AsAddOn() Option {
+	return func(b *builder) { b.license.AddOn = true }
+}
+
+// NewClient returns a Client backed by MemoryStorage and preloaded with
+// licenses (base license first), signed by the test issuer. cfg may be nil;
+// PublicKey and Storage are always overridden, and Init runs with
+// ValidateOnStartup so load errors fail the test. Expired licenses still load.
+func NewClient(t testing.TB, cfg *ilicense.Config, licenses ...*ilicense.License) *ilicense.Client {
+	t.Helper()
+	var c ilicense.Config
+	if cfg == nil {
+		c = ilicense.DefaultConfig()
+	} else {
+		c = *cfg
+	}
+	codes := make([]string, len(licenses))
+	for i, l := range licenses {
+		codes[i] = Sign(t, l)
+	}
+	c.PublicKey = PublicKey()
+	c.Storage = NewMemoryStorage(strings.Join(codes, "\n"))
+	c.Enabled = true
+	c.ValidateOnStartup = true
+	c.AllowStartWhenExpired = true
+
+	client := ilicense.NewClient(&c)
+	if err := client.Init(); err != nil {
+		t.Fatalf("ilicensetest: init client: %v", err)
+	}
+	if len(licenses) > 0 && client.GetCurrentLicense() == nil {
+		t.Fatalf("ilicensetest: licenses were not loaded")
+	}
+	return client
+}
+
+// FakeClock is a manually advanced ilicense.Clock.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a clock stopped at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now implements ilicense.Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// MemoryStorage is an in-memory ilicense.Storage.
+type MemoryStorage struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+// NewMemoryStorage returns storage holding content; empty content means nothing is stored.
+func NewMemoryStorage(content string) *MemoryStorage {
+	s := &MemoryStorage{}
+	if content != "" {
+		s.data = []byte(content)
+	}
+	return s
+}
+
+// Load implements ilicense.Storage.
+func (s *MemoryStorage) Load(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return nil, nil
+	}
+	return append([]byte(nil), s.data...), nil
+}
+
+// Save implements ilicense.Storage.
+func (s *MemoryStorage) Save(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = append([]byte(nil), data...)
+	return nil
+}
+
+// Remove implements ilicense.Storage.
+func (s *MemoryStorage) Remove(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = nil
+	return nil
+}
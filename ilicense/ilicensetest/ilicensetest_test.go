@@ -0,0 +1,55 @@
+package ilicensetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+func TestNewClientWithFakeClock(t *testing.T) {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	cfg := ilicense.DefaultConfig()
+	cfg.Clock = clock
+
+	client := NewClient(t, &cfg, NewLicense(
+		IssuedAt(start),
+		ExpiringIn(48*time.Hour),
+		WithModules("m-a", "m-b"),
+		WithLimit("seats", 10),
+	))
+
+	if err := client.CheckModule("m-b"); err != nil {
+		t.Fatalf("expected module m-b, got %v", err)
+	}
+	if seats, _ := client.GetCurrentLicense().Limit("seats"); seats != 10 {
+		t.Fatalf("expected seats limit 10, got %d", seats)
+	}
+	if days := client.GetCurrentLicense().DaysLeft; days != 2 {
+		t.Fatalf("expected days left from fake clock, got %d", days)
+	}
+
+	clock.Advance(72 * time.Hour)
+	if status, err := client.CheckLicenseStatus(); status != ilicense.LicenseStatusExpired || !errors.Is(err, ilicense.ErrLicenseExpired) {
+		t.Fatalf("expected expired status after advancing clock, got %q %v", status, err)
+	}
+}
+
+func TestNewClientWithAddOn(t *testing.T) {
+	client := NewClient(t, nil,
+		NewLicense(WithModules("m-a"), WithMaxInstances(1)),
+		NewLicense(WithCode("addon", 1), AsAddOn(), WithModules("m-x"), WithMaxInstances(2), Perpetual()),
+	)
+	if !client.HasModule("m-x") || client.GetCurrentLicense().MaxInstances != 3 {
+		t.Fatalf("unexpected effective license: %+v", client.GetCurrentLicense())
+	}
+}
+
+func TestNewClientWithoutLicense(t *testing.T) {
+	client := NewClient(t, nil)
+	if err := client.CheckLicense(); !errors.Is(err, ilicense.ErrLicenseNotFound) {
+		t.Fatalf("expected ErrLicenseNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package ilicense
+
+import (
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+// Validation check names reported by Inspect. Machine binding and revocation
+// are not supported yet and are always reported as skipped.
+const (
+	CheckSignature  = "signature"
+	CheckProduct    = "product"
+	CheckExpiry     = "expiry"
+	CheckMachine    = "machine"
+	CheckRevocation = "revocation"
+)
+
+// CheckResult is the outcome of a single validation check.
+type CheckResult string
+
+const (
+	CheckPassed  CheckResult = "pass"
+	CheckFailed  CheckResult = "fail"
+	CheckSkipped CheckResult = "skip"
+)
+
+// ValidationCheck records one check performed by Inspect.
+type ValidationCheck struct {
+	Name   string      `json:"name"`
+	Result CheckResult `json:"result"`
+	Detail string      `json:"detail,omitempty"`
+	Code   string      `json:"code,omitempty"` // ErrorCode of a failed check
+	Err    error       `json:"-"`
+}
+
+// ValidationReport lists every check performed on an activation code, in order.
+type ValidationReport struct {
+	Checks []ValidationCheck `json:"checks"`
+}
+
+// Passed reports whether no check failed.
+func (r *ValidationReport) Passed() bool {
+	for _, c := range r.Checks {
+		if c.Result == CheckFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns the named check.
+func (r *ValidationReport) Check(name string) (ValidationCheck, bool) {
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ValidationCheck{}, false
+}
+
+func (r *ValidationReport) record(name string, err error) {
+	c := ValidationCheck{Name: name, Result: CheckPassed, Err: err}
+	if err != nil {
+		c.Result = CheckFailed
+		c.Detail = err.Error()
+		c.Code = ErrorCode(err)
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+func (r *ValidationReport) skip(name, detail string) {
+	r.Checks = append(r.Checks, ValidationCheck{Name: name, Result: CheckSkipped, Detail: detail})
+}
+
+// Inspect verifies and decodes an activation code without persisting it or
+// touching any client state. The returned License is nil only when the code
+// cannot be decoded; the error is the first failed check, if any.
+func Inspect(publicKey, activationCode string) (*License, *ValidationReport, error) {
+	return NewClient(&Config{PublicKey: publicKey}).Inspect(activationCode)
+}
+
+// Inspect is like the package-level Inspect but also applies the client's
+// product binding and edition catalogue. It has no side effects.
+func (m *Client) Inspect(activationCode string) (*License, *ValidationReport, error) {
+	report := &ValidationReport{}
+	raw, err := m.validate(activationCode)
+	report.record(CheckSignature, err)
+	if err != nil {
+		return nil, report, err
+	}
+	license := m.prepareLicense(raw)
+
+	var firstErr error
+	if m.config.ProductCode == "" && m.config.ProductVersion == "" {
+		report.skip(CheckProduct, "no product configured")
+	} else {
+		firstErr = m.checkProduct(license)
+		report.record(CheckProduct, firstErr)
+	}
+
+	var expiryErr error
+	if license.IsExpired(m.now()) {
+		expiryErr = ErrLicenseExpired
+	}
+	report.record(CheckExpiry, expiryErr)
+	report.skip(CheckMachine, "not supported")
+	report.skip(CheckRevocation, "not supported")
+	if firstErr == nil {
+		firstErr = expiryErr
+	}
+	return license, report, firstErr
+}
+
+// DecodeUnverified decodes an activation code WITHOUT verifying its signature.
+// The result must not be trusted; it is intended for support diagnostics.
+func DecodeUnverified(activationCode string) (*License, error) {
+	raw, err := licensing.Decode(activationCode)
+	if err != nil {
+		return nil, err
+	}
+	return fromCoreLicense(raw), nil
+}
@@ -0,0 +1,66 @@
+package ilicense
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+func TestInspectInvalidCode(t *testing.T) {
+	license, report, err := Inspect("invalid-public-key", "not-a-code")
+	if err == nil || license != nil {
+		t.Fatalf("expected inspection error without license, got %v %v", license, err)
+	}
+	if report.Passed() {
+		t.Fatalf("expected report to fail")
+	}
+	check, ok := report.Check(CheckSignature)
+	if !ok || check.Result != CheckFailed || !errors.Is(check.Err, err) {
+		t.Fatalf("unexpected signature check: %+v", check)
+	}
+	if _, ok := report.Check(CheckExpiry); ok {
+		t.Fatalf("did not expect expiry check after signature failure")
+	}
+}
+
+func TestInspectReportsChecks(t *testing.T) {
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ExpireAt: time.Now().Add(-time.Hour)})
+
+	license, report, err := Inspect(testPublicKey(t), code)
+	if !errors.Is(err, ErrLicenseExpired) || license == nil || license.LicenseCode != "L1" {
+		t.Fatalf("expected expired license, got %+v %v", license, err)
+	}
+	names := []string{CheckSignature, CheckProduct, CheckExpiry, CheckMachine, CheckRevocation}
+	want := []CheckResult{CheckPassed, CheckSkipped, CheckFailed, CheckSkipped, CheckSkipped}
+	if len(report.Checks) != len(names) {
+		t.Fatalf("expected %d checks, got %+v", len(names), report.Checks)
+	}
+	for i, name := range names {
+		if c := report.Checks[i]; c.Name != name || c.Result != want[i] {
+			t.Fatalf("expected %s check %q, got %+v", name, want[i], c)
+		}
+	}
+	if c, _ := report.Check(CheckRevocation); c.Detail != "not supported" {
+		t.Fatalf("expected unsupported revocation check, got %+v", c)
+	}
+}
+
+func TestInspectPassesValidCode(t *testing.T) {
+	cfg := activationConfig(t)
+	cfg.ProductCode = "p-a"
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", ProductCode: "p-a", ExpireAt: time.Now().Add(time.Hour)})
+
+	client := NewClient(&cfg)
+	license, report, err := client.Inspect(code)
+	if err != nil || license == nil || !report.Passed() {
+		t.Fatalf("expected valid code to pass, got %+v %v", report, err)
+	}
+	if c, _ := report.Check(CheckProduct); c.Result != CheckPassed {
+		t.Fatalf("expected product check to pass, got %+v", c)
+	}
+	if client.GetCurrentLicense() != nil {
+		t.Fatalf("Inspect must not load the license")
+	}
+}
@@ -0,0 +1,125 @@
+// Package issuer produces activation codes in exactly the format verified by
+// ilicense, for end-to-end tests and self-hosted issuance.
+//
+// The wire format is the license JSON signed with RSA PKCS#1 v1.5 over SHA-256
+// (the only algorithm ilicense verifies), wrapped in length-prefixed,
+// URL-safe base64 binary. Keep private keys on the issuing side only.
+package issuer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+// DefaultKeyBits is the RSA key size used when GenerateKey is given 0.
+const DefaultKeyBits = 2048
+
+// Issuer signs licenses with an RSA private key.
+type Issuer struct {
+	key *rsa.PrivateKey
+}
+
+// New creates an issuer for the given private key.
+func New(key *rsa.PrivateKey) *Issuer {
+	return &Issuer{key: key}
+}
+
+// Generate creates an issuer with a fresh key of DefaultKeyBits.
+func Generate() (*Issuer, error) {
+	key, err := GenerateKey(0)
+	if err != nil {
+		return nil, err
+	}
+	return New(key), nil
+}
+
+// PrivateKey returns the signing key.
+func (i *Issuer) PrivateKey() *rsa.PrivateKey {
+	return i.key
+}
+
+// PublicKey returns the PEM encoded public key to put in ilicense.Config.PublicKey.
+func (i *Issuer) PublicKey() string {
+	s, err := EncodePublicKey(&i.key.PublicKey)
+	if err != nil {
+		// Marshalling a valid RSA public key cannot fail.
+		panic(err)
+	}
+	return s
+}
+
+// Sign returns an activation code for license. Derived fields such as Valid,
+// DaysLeft, EditionModules, Trial and ModuleExpiry are not signed.
+func (i *Issuer) Sign(license *ilicense.License) (string, error) {
+	if license == nil {
+		return "", errors.New("license is nil")
+	}
+	data, err := json.Marshal(license)
+	if err != nil {
+		return "", err
+	}
+	var payload licensing.License
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Keep numeric claims as json.Number so integers above 2^53 are signed exactly.
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return "", err
+	}
+	payload.Valid = false
+	payload.DaysLeft = 0
+	return licensing.Sign(i.key, &payload)
+}
+
+// GenerateKey creates an RSA private key; bits <= 0 uses DefaultKeyBits.
+func GenerateKey(bits int) (*rsa.PrivateKey, error) {
+	if bits <= 0 {
+		bits = DefaultKeyBits
+	}
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// EncodePrivateKey returns key as a PKCS#8 PEM block.
+func EncodePrivateKey(key *rsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// EncodePublicKey returns key as a PKIX PEM block.
+func EncodePublicKey(key *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// ParsePrivateKey parses a PKCS#8 or PKCS#1 PEM encoded RSA private key.
+func ParsePrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("invalid PEM private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+	return rsaKey, nil
+}
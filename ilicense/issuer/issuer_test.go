@@ -0,0 +1,89 @@
+package issuer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xbingbo/ilicense-client-go/ilicense"
+)
+
+func TestSignedCodeActivates(t *testing.T) {
+	iss, err := Generate()
+	if err != nil {
+		t.Fatalf("unexpected generate error: %v", err)
+	}
+	code, err := iss.Sign(&ilicense.License{
+		LicenseCode:  "L1",
+		CustomerName: "ACME",
+		ProductCode:  "p-a",
+		ExpireAt:     time.Now().Add(30 * 24 * time.Hour),
+		Modules:      "m-a,m-b",
+		Claims:       map[string]any{"region": "eu"},
+		Trial:        true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+
+	cfg := ilicense.DefaultConfig()
+	cfg.PublicKey = iss.PublicKey()
+	cfg.StoragePath = t.TempDir() + "/license.dat"
+	cfg.ProductCode = "p-a"
+	client := ilicense.NewClient(&cfg)
+
+	license, err := client.Activate(code)
+	if err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+	if license.CustomerName != "ACME" || license.Trial {
+		t.Fatalf("unexpected license: %+v", license)
+	}
+	if region, _ := license.StringClaim("region"); region != "eu" {
+		t.Fatalf("expected signed claim, got %q", region)
+	}
+	if err := client.CheckModule("m-b"); err != nil {
+		t.Fatalf("expected module m-b, got %v", err)
+	}
+}
+
+func TestPrivateKeyRoundTrip(t *testing.T) {
+	key, err := GenerateKey(1024)
+	if err != nil {
+		t.Fatalf("unexpected generate error: %v", err)
+	}
+	encoded, err := EncodePrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	parsed, err := ParsePrivateKey(encoded)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if !parsed.Equal(key) {
+		t.Fatalf("expected parsed key to equal original")
+	}
+}
+
+func TestSignKeepsLargeIntegerClaims(t *testing.T) {
+	iss, err := Generate()
+	if err != nil {
+		t.Fatalf("unexpected generate error: %v", err)
+	}
+	code, err := iss.Sign(&ilicense.License{
+		LicenseCode: "L1",
+		Claims:      map[string]any{"quota": int64(9007199254740993), "ratio": 1.5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+	license, _, err := ilicense.Inspect(iss.PublicKey(), code)
+	if err != nil {
+		t.Fatalf("unexpected inspect error: %v", err)
+	}
+	if quota, ok := license.IntClaim("quota"); !ok || quota != 9007199254740993 {
+		t.Fatalf("expected exact integer claim, got %d %v", quota, ok)
+	}
+	if _, ok := license.IntClaim("ratio"); ok {
+		t.Fatalf("expected fractional claim to stay fractional")
+	}
+}
@@ -19,8 +19,36 @@ type License struct {
 	Modules      string    `json:"modules"`
 	MaxInstances int       `json:"max_instances"`
 
+	// MaintenanceExpireAt ends update entitlement: builds released after it are
+	// rejected by CheckVersionEntitled while runtime use stays valid. Zero means unlimited.
+	MaintenanceExpireAt time.Time `json:"maintenance_expire_at"`
+	// ProductVersions is a semver constraint such as ">=1.2.0 <2.0.0" limiting
+	// which Config.ProductVersion values may run under this license.
+	ProductVersions string `json:"product_versions,omitempty"`
+	// Revision orders successive issues of the same LicenseCode; Activate
+	// refuses a lower revision unless forced.
+	Revision int `json:"revision,omitempty"`
+	// AddOn marks a license that extends the base license instead of replacing it.
+	AddOn bool `json:"add_on,omitempty"`
+	// Limits holds named numeric limits such as seats; add-on limits are summed.
+	Limits map[string]int64 `json:"limits,omitempty"`
+
+	// Claims holds custom signed attributes such as region, edition or support tier.
+	Claims map[string]any `json:"claims,omitempty"`
+	// Edition names the purchased edition; Editions is an optional signed catalogue
+	// that takes precedence over Config.Editions.
+	Edition  string         `json:"edition,omitempty"`
+	Editions EditionCatalog `json:"editions,omitempty"`
+
 	Valid    bool  `json:"valid"`
 	DaysLeft int64 `json:"days_left"`
+	// EditionModules lists modules inherited from the resolved edition.
+	EditionModules []string `json:"edition_modules,omitempty"`
+	// Trial reports a locally generated trial license.
+	Trial bool `json:"trial,omitempty"`
+	// ModuleExpiry lists modules that expire before the license itself,
+	// typically because they were granted by a shorter add-on license.
+	ModuleExpiry map[string]time.Time `json:"module_expiry,omitempty"`
 }
 
 // IsExpired reports whether ExpireAt is before the given time.
@@ -31,9 +59,37 @@ func (l *License) IsExpired(now time.Time) bool {
 	return l.ExpireAt.Before(now)
 }
 
-// HasModule reports whether Modules contains an exact module token.
+// IsPerpetual reports whether the license never expires.
+func (l *License) IsPerpetual() bool {
+	return l.ExpireAt.IsZero()
+}
+
+// CoversBuild reports whether a build released at buildDate falls within maintenance.
+func (l *License) CoversBuild(buildDate time.Time) bool {
+	if l.MaintenanceExpireAt.IsZero() {
+		return true
+	}
+	return !buildDate.After(l.MaintenanceExpireAt)
+}
+
+// HasModule reports whether Modules or the resolved edition contains an exact
+// module token that has not passed its ModuleExpiry.
 func (l *License) HasModule(moduleName string) bool {
+	return l.hasModuleAt(moduleName, time.Now())
+}
+
+func (l *License) hasModuleAt(moduleName string, now time.Time) bool {
 	moduleName = strings.TrimSpace(moduleName)
+	if !l.grantsModule(moduleName) {
+		return false
+	}
+	if expireAt, ok := l.ModuleExpiry[moduleName]; ok && expireAt.Before(now) {
+		return false
+	}
+	return true
+}
+
+func (l *License) grantsModule(moduleName string) bool {
 	if moduleName == "" {
 		return false
 	}
@@ -42,5 +98,64 @@ func (l *License) HasModule(moduleName string) bool {
 			return true
 		}
 	}
+	for _, m := range l.EditionModules {
+		if m == moduleName {
+			return true
+		}
+	}
 	return false
 }
+
+// ModuleNames lists the explicit and edition modules without duplicates,
+// ignoring ModuleExpiry.
+func (l *License) ModuleNames() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range licenseModules(l) {
+		if !seen[m] {
+			seen[m] = true
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Limit returns a named numeric limit.
+func (l *License) Limit(name string) (int64, bool) {
+	v, ok := l.Limits[name]
+	return v, ok
+}
+
+// EditionName returns Edition, falling back to the "edition" claim.
+func (l *License) EditionName() string {
+	if l.Edition != "" {
+		return l.Edition
+	}
+	name, _ := l.StringClaim("edition")
+	return name
+}
+
+// clone returns a deep copy so callers cannot mutate shared client state.
+func (l *License) clone() *License {
+	out := *l
+	if l.Claims != nil {
+		out.Claims = copyClaimMap(l.Claims)
+	}
+	out.Editions = l.Editions.clone()
+	if l.EditionModules != nil {
+		out.EditionModules = append([]string(nil), l.EditionModules...)
+	}
+	if l.Limits != nil {
+		out.Limits = make(map[string]int64, len(l.Limits))
+		for k, v := range l.Limits {
+			out.Limits[k] = v
+		}
+	}
+	if l.ModuleExpiry != nil {
+		out.ModuleExpiry = make(map[string]time.Time, len(l.ModuleExpiry))
+		for k, v := range l.ModuleExpiry {
+			out.ModuleExpiry[k] = v
+		}
+	}
+	return &out
+}
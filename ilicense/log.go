@@ -0,0 +1,128 @@
+package ilicense
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Attribute keys used in SDK log records.
+const (
+	logKeyLicenseCode  = "license_code"
+	logKeyCustomerCode = "customer_code"
+	logKeyStatus       = "status"
+	logKeyModule       = "module"
+	logKeyError        = "error"
+	logKeyErrorKind    = "error_kind"
+)
+
+// newLogger returns the logger configured by SlogLogger or Logger, or one
+// that discards everything.
+func newLogger(cfg *Config) *slog.Logger {
+	switch {
+	case cfg.SlogLogger != nil:
+		return cfg.SlogLogger
+	case cfg.Logger != nil:
+		return slog.New(NewLoggerHandler(cfg.Logger, nil))
+	default:
+		return slog.New(slog.DiscardHandler)
+	}
+}
+
+// licenseAttrs identifies a license in log records.
+func licenseAttrs(l *License) slog.Attr {
+	return slog.Group("",
+		slog.String(logKeyLicenseCode, l.LicenseCode),
+		slog.String(logKeyCustomerCode, l.CustomerCode),
+	)
+}
+
+// errorAttrs describes err in log records.
+func errorAttrs(err error) slog.Attr {
+	return slog.Group("",
+		slog.String(logKeyError, err.Error()),
+		slog.String(logKeyErrorKind, ErrorCode(err)),
+	)
+}
+
+// NewLoggerHandler adapts a legacy Logger to slog. Records at level or above
+// are written with Println as "LEVEL message key=value ..."; a nil level
+// means slog.LevelDebug, so every message the Logger received before slog
+// still reaches it.
+func NewLoggerHandler(l Logger, level slog.Leveler) slog.Handler {
+	if level == nil {
+		level = slog.LevelDebug
+	}
+	return &loggerHandler{logger: l, level: level}
+}
+
+type loggerHandler struct {
+	logger Logger
+	level  slog.Leveler
+	attrs  string
+	group  string
+}
+
+func (h *loggerHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *loggerHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.group, a)
+		return true
+	})
+	h.logger.Println(b.String())
+	return nil
+}
+
+func (h *loggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&b, h.group, a)
+	}
+	next := *h
+	next.attrs = b.String()
+	return &next
+}
+
+func (h *loggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.group = h.group + name + "."
+	return &next
+}
+
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, prefix, ga)
+		}
+		return
+	}
+	b.WriteByte(' ')
+	b.WriteString(prefix)
+	b.WriteString(a.Key)
+	b.WriteByte('=')
+	v := a.Value.String()
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	b.WriteString(v)
+}
@@ -0,0 +1,77 @@
+package ilicense
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	licensing "github.com/xbingbo/ilicense-client-go/internal/licensing"
+)
+
+type recordingLogger struct{ lines []string }
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+func (l *recordingLogger) Println(v ...any) {
+	l.lines = append(l.lines, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+func TestSlogLoggerReceivesStructuredAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := activationConfig(t)
+	cfg.SlogLogger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(&cfg)
+
+	code := signTestLicense(t, licensing.License{LicenseCode: "L1", CustomerCode: "C1", ExpireAt: time.Now().Add(time.Hour)})
+	if _, err := client.Activate(code); err != nil {
+		t.Fatalf("unexpected activate error: %v", err)
+	}
+	_ = client.CheckModule("m-x")
+
+	var activated, denied map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		switch rec["msg"] {
+		case "license activated":
+			activated = rec
+		case "module check denied":
+			denied = rec
+		}
+	}
+	if activated["level"] != "INFO" || activated["license_code"] != "L1" || activated["customer_code"] != "C1" {
+		t.Fatalf("unexpected activation record: %v", activated)
+	}
+	if denied["level"] != "DEBUG" || denied["module"] != "m-x" || denied["error_kind"] != "module_unauthorized" {
+		t.Fatalf("unexpected module record: %v", denied)
+	}
+}
+
+func TestLegacyLoggerAdapter(t *testing.T) {
+	legacy := &recordingLogger{}
+	logger := slog.New(NewLoggerHandler(legacy, slog.LevelInfo)).With(slog.String("app", "demo"))
+	logger.Debug("hidden")
+	logger.Warn("license expired", licenseAttrs(&License{LicenseCode: "L1", CustomerCode: "C1"}), slog.String("note", "two words"))
+
+	want := `WARN license expired app=demo license_code=L1 customer_code=C1 note="two words"`
+	if len(legacy.lines) != 1 || legacy.lines[0] != want {
+		t.Fatalf("unexpected legacy output %q", legacy.lines)
+	}
+}
+
+func TestLegacyLoggerDefaultsToDebug(t *testing.T) {
+	legacy := &recordingLogger{}
+	logger := slog.New(NewLoggerHandler(legacy, nil))
+	logger.Debug("license not activated, skipping check")
+
+	if len(legacy.lines) != 1 || legacy.lines[0] != "DEBUG license not activated, skipping check" {
+		t.Fatalf("unexpected legacy output %q", legacy.lines)
+	}
+}
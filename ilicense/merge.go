@@ -0,0 +1,140 @@
+package ilicense
+
+import (
+	"strings"
+	"time"
+)
+
+// storedLicense pairs an activation code with the license it validated to.
+type storedLicense struct {
+	code    string
+	license *License
+}
+
+// withLicense returns entries updated with a newly activated license.
+// A base license replaces the current base license; an add-on replaces an
+// entry with the same LicenseCode or is appended. The base always comes first.
+func withLicense(entries []storedLicense, next storedLicense) []storedLicense {
+	out := make([]storedLicense, 0, len(entries)+1)
+	if !next.license.AddOn {
+		out = append(out, next)
+	}
+	replaced := false
+	for _, e := range entries {
+		switch {
+		case e.license.LicenseCode == next.license.LicenseCode && next.license.AddOn:
+			out = append(out, next)
+			replaced = true
+		case e.license.LicenseCode == next.license.LicenseCode:
+		case !e.license.AddOn && !next.license.AddOn:
+		default:
+			out = append(out, e)
+		}
+	}
+	if next.license.AddOn && !replaced {
+		out = append(out, next)
+	}
+	return out
+}
+
+// mergeLicenses computes the effective entitlement of several licenses:
+// the base license fields with the union of modules, summed limits and
+// per-module expiry for modules that expire before the base license.
+// Add-ons that are already expired at now contribute nothing, and add-ons
+// without a base license grant nothing at all.
+func mergeLicenses(licenses []*License, now time.Time) *License {
+	var base *License
+	for _, l := range licenses {
+		if !l.AddOn {
+			base = l
+			break
+		}
+	}
+	if base == nil {
+		return nil
+	}
+	if len(licenses) == 1 {
+		return base.clone()
+	}
+	eff := base.clone()
+	eff.AddOn = false
+
+	var modules []string
+	seen := make(map[string]bool)
+	expiry := make(map[string]time.Time)
+	for _, l := range licenses {
+		if l != base && l.IsExpired(now) {
+			continue
+		}
+		for _, m := range licenseModules(l) {
+			if !seen[m] {
+				seen[m] = true
+				modules = append(modules, m)
+				expiry[m] = l.ExpireAt
+				continue
+			}
+			if cur := expiry[m]; !cur.IsZero() && (l.ExpireAt.IsZero() || l.ExpireAt.After(cur)) {
+				expiry[m] = l.ExpireAt
+			}
+		}
+		if l == base {
+			continue
+		}
+		eff.MaxInstances += l.MaxInstances
+		for name, v := range l.Limits {
+			if eff.Limits == nil {
+				eff.Limits = make(map[string]int64)
+			}
+			eff.Limits[name] += v
+		}
+		for k, v := range l.Claims {
+			if _, ok := eff.Claims[k]; !ok {
+				if eff.Claims == nil {
+					eff.Claims = make(map[string]any)
+				}
+				eff.Claims[k] = copyClaimValue(v)
+			}
+		}
+	}
+
+	eff.Modules = strings.Join(modules, ",")
+	eff.EditionModules = nil
+	eff.ModuleExpiry = nil
+	for m, expireAt := range expiry {
+		if expireAt.IsZero() || (!eff.ExpireAt.IsZero() && !expireAt.Before(eff.ExpireAt)) {
+			continue
+		}
+		if eff.ModuleExpiry == nil {
+			eff.ModuleExpiry = make(map[string]time.Time)
+		}
+		eff.ModuleExpiry[m] = expireAt
+	}
+	return eff
+}
+
+// licenseModules lists the explicit and edition modules of a single license.
+func licenseModules(l *License) []string {
+	var out []string
+	for _, m := range strings.Split(l.Modules, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			out = append(out, m)
+		}
+	}
+	return append(out, l.EditionModules...)
+}
+
+// splitStoredCodes splits storage content into activation codes, one per line.
+func splitStoredCodes(data string) []string {
+	var codes []string
+	for _, line := range strings.Split(data, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			codes = append(codes, line)
+		}
+	}
+	return codes
+}
+
+// normalizeCode removes whitespace so a code always fits on one storage line.
+func normalizeCode(code string) string {
+	return strings.Join(strings.Fields(code), "")
+}
@@ -0,0 +1,124 @@
+package ilicense
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeLicenses(t *testing.T) {
+	now := time.Now()
+	base := &License{LicenseCode: "base", ExpireAt: now.AddDate(1, 0, 0), Modules: "m-a,m-b", MaxInstances: 2, Limits: map[string]int64{"seats": 10}}
+	seats := &License{LicenseCode: "seats", AddOn: true, ExpireAt: now.AddDate(2, 0, 0), MaxInstances: 3, Limits: map[string]int64{"seats": 5}}
+	report := &License{LicenseCode: "report", AddOn: true, ExpireAt: now.AddDate(0, 1, 0), Modules: "m-report,m-a"}
+	stale := &License{LicenseCode: "stale", AddOn: true, ExpireAt: now.Add(-time.Hour), Modules: "m-old", MaxInstances: 100}
+
+	eff := mergeLicenses([]*License{base, seats, report, stale}, now)
+
+	if eff.LicenseCode != "base" || eff.AddOn {
+		t.Fatalf("expected base license fields, got %+v", eff)
+	}
+	if eff.Modules != "m-a,m-b,m-report" {
+		t.Fatalf("unexpected modules: %s", eff.Modules)
+	}
+	if eff.MaxInstances != 5 {
+		t.Fatalf("expected summed instances 5, got %d", eff.MaxInstances)
+	}
+	if seatsLimit, _ := eff.Limit("seats"); seatsLimit != 15 {
+		t.Fatalf("expected summed seats 15, got %d", seatsLimit)
+	}
+	want := map[string]time.Time{"m-report": report.ExpireAt}
+	if !reflect.DeepEqual(eff.ModuleExpiry, want) {
+		t.Fatalf("unexpected module expiry: %v", eff.ModuleExpiry)
+	}
+	if base.Limits["seats"] != 10 {
+		t.Fatalf("expected merge not to mutate base license")
+	}
+}
+
+func TestMergeIgnoresOrphanAddOns(t *testing.T) {
+	now := time.Now()
+	addOn := &License{LicenseCode: "report", AddOn: true, ExpireAt: now.AddDate(1, 0, 0), Modules: "m-report"}
+	if eff := mergeLicenses([]*License{addOn}, now); eff != nil {
+		t.Fatalf("expected no entitlement from a lone add-on, got %+v", eff)
+	}
+	if eff := mergeLicenses([]*License{addOn, addOn}, now); eff != nil {
+		t.Fatalf("expected no entitlement from add-ons only, got %+v", eff)
+	}
+}
+
+func TestHasModuleHonorsModuleExpiry(t *testing.T) {
+	l := &License{Modules: "m-a,m-b", ModuleExpiry: map[string]time.Time{"m-b": time.Now().Add(-time.Minute)}}
+	if !l.HasModule("m-a") {
+		t.Fatalf("expected m-a to be granted")
+	}
+	if l.HasModule("m-b") {
+		t.Fatalf("did not expect expired add-on module m-b")
+	}
+}
+
+func TestWithLicense(t *testing.T) {
+	entry := func(code string, addOn bool) storedLicense {
+		return storedLicense{code: code, license: &License{LicenseCode: code, AddOn: addOn}}
+	}
+	codes := func(entries []storedLicense) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.code)
+		}
+		return out
+	}
+
+	entries := withLicense(nil, entry("add-1", true))
+	entries = withLicense(entries, entry("base-1", false))
+	entries = withLicense(entries, entry("add-2", true))
+	if got := codes(entries); !reflect.DeepEqual(got, []string{"base-1", "add-1", "add-2"}) {
+		t.Fatalf("unexpected entries: %v", got)
+	}
+
+	entries = withLicense(entries, entry("base-2", false))
+	entries = withLicense(entries, entry("add-1", true))
+	if got := codes(entries); !reflect.DeepEqual(got, []string{"base-2", "add-1", "add-2"}) {
+		t.Fatalf("unexpected entries after replacement: %v", got)
+	}
+}
+
+func TestSplitStoredCodes(t *testing.T) {
+	got := splitStoredCodes("code-a\r\n\n  code-b \n")
+	if !reflect.DeepEqual(got, []string{"code-a", "code-b"}) {
+		t.Fatalf("unexpected codes: %v", got)
+	}
+	if normalizeCode(" ab\ncd\t") != "abcd" {
+		t.Fatalf("expected whitespace to be removed")
+	}
+}
+
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func TestExpiredAddOnStopsCountingLimits(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	cfg := DefaultConfig()
+	cfg.Clock = clock
+	client := NewClient(&cfg)
+	client.setEntries([]storedLicense{
+		{code: "base", license: &License{LicenseCode: "base", ExpireAt: clock.now.AddDate(1, 0, 0), Modules: "m-a", MaxInstances: 2, Limits: map[string]int64{"seats": 10}}},
+		{code: "add", license: &License{LicenseCode: "add", AddOn: true, ExpireAt: clock.now.Add(24 * time.Hour), Modules: "m-add", MaxInstances: 3, Limits: map[string]int64{"seats": 5}}},
+	})
+	if l := client.GetCurrentLicense(); l.MaxInstances != 5 || l.Limits["seats"] != 15 {
+		t.Fatalf("expected add-on limits while active, got %d %v", l.MaxInstances, l.Limits)
+	}
+
+	clock.now = clock.now.Add(48 * time.Hour)
+	if client.HasModule("m-add") {
+		t.Fatalf("did not expect expired add-on module")
+	}
+	l := client.GetCurrentLicense()
+	if l.MaxInstances != 2 || l.Limits["seats"] != 10 {
+		t.Fatalf("expected base limits after add-on expiry, got %d %v", l.MaxInstances, l.Limits)
+	}
+	if err := client.CheckModule("m-a"); err != nil {
+		t.Fatalf("expected base module after add-on expiry, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package ilicense
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Languages supported by Message. Other tags fall back to LangEN, except
+// tags starting with "zh" which use LangZhCN.
+const (
+	LangZhCN = "zh-CN"
+	LangEN   = "en"
+)
+
+// MessageLicenseExpiring is a message key, alongside the error codes, for
+// warning users about an upcoming expiry; set MessageParams.DaysLeft.
+const MessageLicenseExpiring = "license_expiring"
+
+// MessageParams fills placeholders of catalogue messages.
+type MessageParams struct {
+	Module   string
+	DaysLeft int64
+	// BuildDate and MaintenanceDate are formatted as 2006-01-02.
+	BuildDate       string
+	MaintenanceDate string
+}
+
+var messageCatalog = map[string]map[string]string{
+	LangZhCN: {
+		CodeLicenseNotFound:      "系统尚未激活，请上传许可证激活码。",
+		CodeLicenseExpired:       "许可证已过期，请联系供应商续期。",
+		MessageLicenseExpiring:   "许可证将在 {days} 天后到期，请及时续期。",
+		CodeModuleUnauthorized:   "当前许可证未授权功能模块「{module}」。",
+		moduleUnauthorizedNoName: "当前许可证未授权该功能模块。",
+		CodeMaintenanceExpired:   "当前版本发布于 {build_date}，晚于维护期截止日 {maintenance_date}，请续订维护服务或使用较早版本。",
+		CodeProductMismatch:      "该激活码不适用于本产品或当前版本。",
+		CodeLicenseDowngrade:     "该激活码早于当前许可证，未予应用。",
+		CodeAddOnWithoutBase:     "该激活码为附加许可证，请先激活基础许可证。",
+		CodeTrialTampered:        "试用信息已损坏，请联系供应商。",
+		CodeAuditTampered:        "审计日志校验失败，可能已被篡改。",
+		CodeUnhealthy:            "许可证状态异常。",
+		CodeMalformedEncoding:    "激活码格式不正确，请检查是否完整复制。",
+		CodeTruncatedPayload:     "激活码不完整，请检查是否完整复制。",
+		CodeInvalidPayload:       "激活码内容无法识别，请联系供应商。",
+		CodeInvalidPublicKey:     "许可证公钥配置错误，请联系管理员。",
+		CodeUnsupportedAlgorithm: "许可证公钥类型不受支持，请联系管理员。",
+		CodeSignatureInvalid:     "激活码签名校验失败，请确认激活码由正确的供应商签发。",
+		CodeCanceled:             "操作已取消。",
+		CodeDeadlineExceeded:     "操作超时，请稍后重试。",
+		CodeInvalidConfig:        "许可证配置错误，请联系管理员。",
+		CodeStorageError:         "无法读写许可证文件，请检查文件权限。",
+		CodeUnknown:              "许可证校验失败。",
+	},
+	LangEN: {
+		CodeLicenseNotFound:      "This product has not been activated. Please upload a license activation code.",
+		CodeLicenseExpired:       "Your license has expired. Please contact your vendor to renew it.",
+		MessageLicenseExpiring:   "Your license expires in {days} days. Please renew it soon.",
+		CodeModuleUnauthorized:   "Your license does not include the \"{module}\" module.",
+		moduleUnauthorizedNoName: "Your license does not include this module.",
+		CodeMaintenanceExpired:   "This version was released on {build_date}, after your maintenance period ended on {maintenance_date}. Renew maintenance or use an earlier version.",
+		CodeProductMismatch:      "This activation code is not valid for this product or version.",
+		CodeLicenseDowngrade:     "This activation code is older than the current license and was not applied.",
+		CodeAddOnWithoutBase:     "This activation code is an add-on. Please activate the base license first.",
+		CodeTrialTampered:        "Trial information is corrupted. Please contact your vendor.",
+		CodeAuditTampered:        "The audit log failed verification and may have been altered.",
+		CodeUnhealthy:            "The license is not in a healthy state.",
+		CodeMalformedEncoding:    "The activation code is not valid. Please check that it was copied completely.",
+		CodeTruncatedPayload:     "The activation code is incomplete. Please check that it was copied completely.",
+		CodeInvalidPayload:       "The activation code content is not recognized. Please contact your vendor.",
+		CodeInvalidPublicKey:     "The license public key is misconfigured. Please contact your administrator.",
+		CodeUnsupportedAlgorithm: "The license public key type is not supported. Please contact your administrator.",
+		CodeSignatureInvalid:     "The activation code signature is invalid. Make sure it was issued by the right vendor.",
+		CodeCanceled:             "The operation was canceled.",
+		CodeDeadlineExceeded:     "The operation timed out. Please try again.",
+		CodeInvalidConfig:        "The license configuration is invalid. Please contact your administrator.",
+		CodeStorageError:         "The license file could not be read or written. Please check file permissions.",
+		CodeUnknown:              "The license check failed.",
+	},
+}
+
+const moduleUnauthorizedNoName = "module_unauthorized_no_name"
+
+// Message returns an end-user friendly text for err in lang, e.g. "zh-CN" or
+// "en", filling parameters such as the module name from typed errors. It
+// returns "" for nil.
+func Message(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	var params MessageParams
+	var moduleErr *ModuleUnauthorizedError
+	var maintenanceErr *MaintenanceExpiredError
+	if errors.As(err, &moduleErr) {
+		params.Module = moduleErr.Module
+	}
+	if errors.As(err, &maintenanceErr) {
+		params.BuildDate = maintenanceErr.BuildDate.Format("2006-01-02")
+		params.MaintenanceDate = maintenanceErr.MaintenanceExpireAt.Format("2006-01-02")
+	}
+	return MessageFor(ErrorCode(err), lang, params)
+}
+
+// MessageFor returns the catalogue text for an error code or
+// MessageLicenseExpiring in lang. Unknown keys use the CodeUnknown text.
+func MessageFor(key, lang string, params MessageParams) string {
+	catalog := messageCatalog[matchLang(lang)]
+	if key == CodeModuleUnauthorized && params.Module == "" {
+		key = moduleUnauthorizedNoName
+	}
+	text, ok := catalog[key]
+	if !ok {
+		text = catalog[CodeUnknown]
+	}
+	return strings.NewReplacer(
+		"{module}", params.Module,
+		"{days}", strconv.FormatInt(params.DaysLeft, 10),
+		"{build_date}", params.BuildDate,
+		"{maintenance_date}", params.MaintenanceDate,
+	).Replace(text)
+}
+
+func matchLang(lang string) string {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(lang)), "zh") {
+		return LangZhCN
+	}
+	return LangEN
+}
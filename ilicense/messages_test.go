@@ -0,0 +1,45 @@
+package ilicense
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+	buildDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	maintenanceEnd := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		err  error
+		lang string
+		want string
+	}{
+		{nil, LangEN, ""},
+		{ErrLicenseNotFound, "zh-CN", "系统尚未激活，请上传许可证激活码。"},
+		{fmt.Errorf("check: %w", ErrLicenseExpired), "en-US", "Your license has expired. Please contact your vendor to renew it."},
+		{&ModuleUnauthorizedError{Module: "报表"}, "zh_CN", "当前许可证未授权功能模块「报表」。"},
+		{&ModuleUnauthorizedError{}, "fr", "Your license does not include this module."},
+		{&MaintenanceExpiredError{BuildDate: buildDate, MaintenanceExpireAt: maintenanceEnd}, LangEN,
+			"This version was released on 2026-03-01, after your maintenance period ended on 2026-01-01. Renew maintenance or use an earlier version."},
+		{&ValidationError{Stage: StageSignature, Reason: ErrSignatureInvalid}, LangZhCN, "激活码签名校验失败，请确认激活码由正确的供应商签发。"},
+	}
+	for _, tc := range cases {
+		if got := Message(tc.err, tc.lang); got != tc.want {
+			t.Fatalf("Message(%v, %q) = %q, want %q", tc.err, tc.lang, got, tc.want)
+		}
+	}
+	if got := MessageFor(MessageLicenseExpiring, LangZhCN, MessageParams{DaysLeft: 7}); got != "许可证将在 7 天后到期，请及时续期。" {
+		t.Fatalf("unexpected expiring message %q", got)
+	}
+}
+
+func TestMessageCatalogComplete(t *testing.T) {
+	for key := range messageCatalog[LangEN] {
+		if _, ok := messageCatalog[LangZhCN][key]; !ok {
+			t.Fatalf("zh-CN catalogue misses %q", key)
+		}
+	}
+	if len(messageCatalog[LangEN]) != len(messageCatalog[LangZhCN]) {
+		t.Fatalf("catalogues differ in size")
+	}
+}
@@ -0,0 +1,27 @@
+package ilicense
+
+import "github.com/xbingbo/ilicense-client-go/internal/semver"
+
+// checkProduct verifies the license is bound to the configured product and version.
+func (m *Client) checkProduct(license *License) error {
+	if want := m.config.ProductCode; want != "" && license.ProductCode != want {
+		return &ProductMismatchError{Field: "product_code", Expected: want, Actual: license.ProductCode}
+	}
+	running := m.config.ProductVersion
+	if running == "" || license.ProductVersions == "" {
+		return nil
+	}
+	mismatch := &ProductMismatchError{Field: "product_version", Expected: license.ProductVersions, Actual: running}
+	constraint, err := semver.ParseConstraint(license.ProductVersions)
+	if err != nil {
+		return mismatch
+	}
+	version, err := semver.Parse(running)
+	if err != nil {
+		return &ConfigError{Field: "product_version", Err: err}
+	}
+	if !constraint.Check(version) {
+		return mismatch
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package ilicense
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckProduct(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProductCode = "p-a"
+	cfg.ProductVersion = "2.3.1"
+	client := NewClient(&cfg)
+
+	if err := client.checkProduct(&License{ProductCode: "p-a", ProductVersions: ">=2.0.0 <3.0.0"}); err != nil {
+		t.Fatalf("expected matching product, got %v", err)
+	}
+
+	err := client.checkProduct(&License{ProductCode: "p-b"})
+	var mismatch *ProductMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Field != "product_code" || mismatch.Expected != "p-a" || mismatch.Actual != "p-b" {
+		t.Fatalf("expected product code mismatch, got %v", err)
+	}
+
+	err = client.checkProduct(&License{ProductCode: "p-a", ProductVersions: "^1.0"})
+	if !errors.Is(err, ErrProductMismatch) || !errors.As(err, &mismatch) || mismatch.Field != "product_version" || mismatch.Actual != "2.3.1" {
+		t.Fatalf("expected product version mismatch, got %v", err)
+	}
+	if err := client.checkProduct(&License{ProductCode: "p-a", ProductVersions: ">=9.0.0 ||"}); !errors.Is(err, ErrProductMismatch) {
+		t.Fatalf("expected empty range not to match every version, got %v", err)
+	}
+	if err := client.checkProduct(&License{ProductCode: "p-a", ProductVersions: ">= 2.0.0"}); err != nil {
+		t.Fatalf("expected spaced operator to parse, got %v", err)
+	}
+}
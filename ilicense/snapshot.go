@@ -0,0 +1,125 @@
+package ilicense
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// licenseState is an immutable snapshot of the loaded licenses. It is swapped
+// atomically on every change so checks never lock or copy the license.
+type licenseState struct {
+	// license is the effective license; it must not be mutated or handed out
+	// without clone.
+	license *License
+	entries []storedLicense
+	// validUntil caches the expiry decision as the last Unix nanosecond at
+	// which the license is valid, math.MaxInt64 when it never expires.
+	validUntil int64
+	// modules maps every granted module to the last Unix nanosecond it is
+	// granted, math.MaxInt64 when it lasts as long as the license.
+	modules map[string]int64
+	// remergeAt is the earliest add-on expiry after which license no longer
+	// reflects entries; zero when nothing changes over time.
+	remergeAt time.Time
+}
+
+func newLicenseState(license *License, entries []storedLicense) *licenseState {
+	if license == nil {
+		return &licenseState{entries: entries}
+	}
+	s := &licenseState{
+		license:    license,
+		entries:    entries,
+		validUntil: validUntil(license.ExpireAt),
+		modules:    make(map[string]int64),
+	}
+	for _, m := range strings.Split(license.Modules, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			s.modules[m] = validUntil(license.ModuleExpiry[m])
+		}
+	}
+	for _, m := range license.EditionModules {
+		if m != "" {
+			s.modules[m] = validUntil(license.ModuleExpiry[m])
+		}
+	}
+	return s
+}
+
+// validUntil converts an expiry time to Unix nanoseconds for integer
+// comparison, mapping zero and times beyond the int64 range to
+// math.MaxInt64.
+func validUntil(expireAt time.Time) int64 {
+	if expireAt.IsZero() || expireAt.After(time.Unix(0, math.MaxInt64)) {
+		return math.MaxInt64
+	}
+	if expireAt.Before(time.Unix(0, math.MinInt64)) {
+		return math.MinInt64
+	}
+	return expireAt.UnixNano()
+}
+
+// mergeState builds the snapshot of entries as effective at now.
+func mergeState(entries []storedLicense, now time.Time) *licenseState {
+	s := newLicenseState(mergeLicenses(entryLicenses(entries), now), entries)
+	for _, e := range entries {
+		l := e.license
+		if !l.AddOn || l.ExpireAt.IsZero() || l.IsExpired(now) {
+			continue
+		}
+		if s.remergeAt.IsZero() || l.ExpireAt.Before(s.remergeAt) {
+			s.remergeAt = l.ExpireAt
+		}
+	}
+	return s
+}
+
+// loadState returns the current snapshot, re-merging it once an add-on has
+// expired so its instances, limits and claims stop counting.
+func (m *Client) loadState(now time.Time) *licenseState {
+	s := m.state.Load()
+	if s == nil || s.remergeAt.IsZero() || !s.remergeAt.Before(now) {
+		return s
+	}
+	next := mergeState(s.entries, now)
+	if !m.state.CompareAndSwap(s, next) {
+		return m.state.Load()
+	}
+	return next
+}
+
+func (s *licenseState) expired(now time.Time) bool {
+	return now.UnixNano() > s.validUntil
+}
+
+// check is CheckLicense against the snapshot.
+func (s *licenseState) check(now time.Time) error {
+	if s == nil || s.license == nil {
+		return ErrLicenseNotFound
+	}
+	if s.expired(now) {
+		return ErrLicenseExpired
+	}
+	return nil
+}
+
+// checkModule is CheckModule against the snapshot.
+func (s *licenseState) checkModule(moduleName string, now time.Time) error {
+	if err := s.check(now); err != nil {
+		return err
+	}
+	if !s.hasModule(moduleName, now) {
+		return &ModuleUnauthorizedError{Module: moduleName}
+	}
+	return nil
+}
+
+// hasModule is License.HasModule resolved from the pre-built module set.
+func (s *licenseState) hasModule(moduleName string, now time.Time) bool {
+	if s == nil || s.license == nil {
+		return false
+	}
+	until, ok := s.modules[strings.TrimSpace(moduleName)]
+	return ok && now.UnixNano() <= until
+}
@@ -0,0 +1,136 @@
+package ilicense
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotModuleSet(t *testing.T) {
+	now := time.Now()
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{
+		ExpireAt:       now.Add(time.Hour),
+		Modules:        " m-a , m-b,",
+		EditionModules: []string{"m-pro"},
+		ModuleExpiry:   map[string]time.Time{"m-b": now.Add(-time.Minute)},
+	})
+
+	for module, want := range map[string]bool{"m-a": true, " m-a ": true, "m-pro": true, "m-b": false, "": false, "m": false} {
+		if got := client.HasModule(module); got != want {
+			t.Fatalf("HasModule(%q) = %v, want %v", module, got, want)
+		}
+	}
+	if err := client.CheckModule("m-a"); err != nil {
+		t.Fatalf("expected granted module, got %v", err)
+	}
+}
+
+func TestSnapshotCachedExpiry(t *testing.T) {
+	expireAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newLicenseState(&License{ExpireAt: expireAt, Modules: "m-a"}, nil)
+	for _, tc := range []struct {
+		now     time.Time
+		expired bool
+	}{
+		{expireAt.Add(-time.Nanosecond), false},
+		{expireAt, false},
+		{expireAt.Add(time.Nanosecond), true},
+	} {
+		if got := s.expired(tc.now); got != tc.expired {
+			t.Fatalf("expired(%v) = %v, want %v", tc.now, got, tc.expired)
+		}
+		if err := s.checkModule("m-a", tc.now); (err != nil) != tc.expired {
+			t.Fatalf("checkModule at %v = %v", tc.now, err)
+		}
+	}
+
+	// Expiry times beyond the nanosecond range never expire.
+	far := newLicenseState(&License{ExpireAt: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)}, nil)
+	if far.expired(time.Now()) || far.check(time.Now()) != nil {
+		t.Fatalf("expected far-future license to be valid")
+	}
+}
+
+func TestSnapshotConcurrentSwap(t *testing.T) {
+	client := NewClient(nil)
+	granted := &License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-a"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_ = client.CheckModule("m-a")
+				_ = client.GetCurrentLicense()
+			}
+		}()
+	}
+	for j := 0; j < 1000; j++ {
+		if j%2 == 0 {
+			client.setCurrentLicense(granted)
+		} else {
+			client.setEntries(nil)
+		}
+	}
+	wg.Wait()
+}
+
+func TestCheckModuleDoesNotAllocate(t *testing.T) {
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-a,m-b"})
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = client.CheckModule("m-b")
+		_ = client.HasModule("m-a")
+		_ = client.CheckLicense()
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations per check, got %v", allocs)
+	}
+}
+
+func BenchmarkCheckModule(b *testing.B) {
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{
+		ExpireAt:       time.Now().Add(time.Hour),
+		Modules:        "m-a,m-b,m-c,m-d,m-e",
+		EditionModules: []string{"m-pro", "m-enterprise"},
+	})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := client.CheckModule("m-enterprise"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCheckLicense(b *testing.B) {
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(time.Hour)})
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := client.CheckLicense(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGuard(b *testing.B) {
+	client := NewClient(nil)
+	client.setCurrentLicense(&License{ExpireAt: time.Now().Add(time.Hour), Modules: "m-a"})
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Guard(ctx, "m-a"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
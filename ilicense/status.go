@@ -7,4 +7,5 @@ const (
 	LicenseStatusValid        LicenseStatus = "valid"
 	LicenseStatusExpired      LicenseStatus = "expired"
 	LicenseStatusNotActivated LicenseStatus = "not_activated"
+	LicenseStatusTrial        LicenseStatus = "trial"
 )
@@ -0,0 +1,85 @@
+package ilicense
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Clock supplies the current time. Config.Clock defaults to the system clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// Storage persists the stored activation codes. Config.Storage defaults to a
+// FileStorage at Config.StoragePath. Implementations should return ctx.Err()
+// once ctx is done.
+type Storage interface {
+	// Load returns the stored content, or nil and no error when nothing is stored.
+	Load(ctx context.Context) ([]byte, error)
+	// Save replaces the stored content.
+	Save(ctx context.Context, data []byte) error
+	// Remove deletes the stored content; removing nothing is not an error.
+	Remove(ctx context.Context) error
+}
+
+// FileStorage stores activation codes in a single file.
+type FileStorage struct {
+	Path string
+}
+
+// Load implements Storage. An empty Path behaves as empty storage.
+func (s FileStorage) Load(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.Path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// Save implements Storage, creating parent directories as needed.
+func (s FileStorage) Save(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.Path == "" {
+		return errors.New("storage path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0o600)
+}
+
+// Remove implements Storage.
+func (s FileStorage) Remove(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.Path == "" {
+		return nil
+	}
+	if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (m *Client) now() time.Time {
+	return m.clock.Now()
+}
@@ -0,0 +1,167 @@
+package ilicense
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTrialDays = 30
+	trialLicenseCode = "trial"
+	// trialClockSkew bounds how far a recorded trial start may lie in the future.
+	trialClockSkew = time.Hour
+)
+
+// TrialConfig controls the built-in local trial used when no license is activated.
+type TrialConfig struct {
+	Enabled bool `json:"enabled"`
+	// Days is the trial length; values <= 0 use 30 days.
+	Days int `json:"days"`
+	// Modules are granted while the trial is active.
+	Modules []string `json:"modules"`
+	// StatePaths lists where the trial start is recorded. Every record is
+	// HMAC-protected and missing records are restored from the remaining ones,
+	// so deleting a single file does not restart the trial. Empty uses a file
+	// beside StoragePath plus one under the user config directory, both named
+	// after the public key and ProductCode.
+	StatePaths []string `json:"state_paths"`
+	// Secret keys the HMAC protecting trial records and is required: the
+	// public key alone would let anyone forge a record. Embed a value unique
+	// to the product that is not published with it.
+	Secret string `json:"-"`
+}
+
+type trialRecord struct {
+	StartedAt time.Time `json:"started_at"`
+	MAC       string    `json:"mac"`
+}
+
+func (m *Client) startTrial() error {
+	start, err := m.loadTrialStart()
+	if err != nil {
+		return err
+	}
+	license := m.trialLicense(start)
+	m.setCurrentLicense(license)
+	if license.IsExpired(m.now()) {
+		m.log.Warn("trial expired", slog.String(logKeyStatus, string(LicenseStatusExpired)), slog.Time("expire_at", license.ExpireAt))
+		return nil
+	}
+	m.log.Info("trial active", slog.String(logKeyStatus, string(LicenseStatusTrial)),
+		slog.Time("expire_at", license.ExpireAt), slog.Int64("days_left", license.DaysLeft))
+	return nil
+}
+
+func (m *Client) trialLicense(start time.Time) *License {
+	days := m.config.Trial.Days
+	if days <= 0 {
+		days = defaultTrialDays
+	}
+	now := m.now()
+	license := &License{
+		LicenseCode: trialLicenseCode,
+		IssueAt:     start,
+		ExpireAt:    start.AddDate(0, 0, days),
+		Modules:     strings.Join(m.config.Trial.Modules, ","),
+		Trial:       true,
+	}
+	license.Valid = !license.IsExpired(now)
+	license.DaysLeft = int64(license.ExpireAt.Sub(now).Hours() / 24)
+	return license
+}
+
+// loadTrialStart returns the earliest valid trial start, recording a new one
+// on first use and restoring records that have been removed.
+func (m *Client) loadTrialStart() (time.Time, error) {
+	if m.config.Trial.Secret == "" {
+		return time.Time{}, &ConfigError{Field: "trial.secret", Err: errors.New("a secret is required to protect trial records")}
+	}
+	paths := m.trialStatePaths()
+	if len(paths) == 0 {
+		return time.Time{}, &ConfigError{Field: "trial.state_paths", Err: errors.New("no trial state path available")}
+	}
+
+	var start time.Time
+	var missing []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				missing = append(missing, path)
+				continue
+			}
+			return time.Time{}, &LicenseError{Msg: "failed to read trial state", Err: err}
+		}
+		var rec trialRecord
+		if err := json.Unmarshal(data, &rec); err != nil || !hmac.Equal([]byte(rec.MAC), []byte(m.trialMAC(rec.StartedAt))) {
+			return time.Time{}, ErrTrialTampered
+		}
+		// A start in the future would extend the trial indefinitely.
+		if rec.StartedAt.After(m.now().Add(trialClockSkew)) {
+			return time.Time{}, ErrTrialTampered
+		}
+		if start.IsZero() || rec.StartedAt.Before(start) {
+			start = rec.StartedAt
+		}
+	}
+
+	if start.IsZero() {
+		start = m.now().UTC().Truncate(time.Second)
+		m.log.Info("starting new trial")
+	}
+	for _, path := range missing {
+		if err := m.writeTrialRecord(path, start); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return start, nil
+}
+
+func (m *Client) writeTrialRecord(path string, start time.Time) error {
+	data, err := json.Marshal(trialRecord{StartedAt: start, MAC: m.trialMAC(start)})
+	if err != nil {
+		return &LicenseError{Msg: "failed to save trial state", Err: err}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return &LicenseError{Msg: "failed to save trial state", Err: err}
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return &LicenseError{Msg: "failed to save trial state", Err: err}
+	}
+	return nil
+}
+
+func (m *Client) trialMAC(start time.Time) string {
+	key := sha256.Sum256([]byte(m.config.PublicKey + "|" + m.config.Trial.Secret))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte("ilicense-trial|" + strconv.FormatInt(start.UnixNano(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// trialStatePaths returns the configured paths or per-product defaults. The
+// default file names carry a digest of the public key and product code so
+// products sharing a directory keep separate trials.
+func (m *Client) trialStatePaths() []string {
+	if len(m.config.Trial.StatePaths) > 0 {
+		return m.config.Trial.StatePaths
+	}
+	sum := sha256.Sum256([]byte(m.config.PublicKey + "|" + m.config.ProductCode))
+	id := hex.EncodeToString(sum[:8])
+	var paths []string
+	if m.config.StoragePath != "" {
+		paths = append(paths, filepath.Join(filepath.Dir(m.config.StoragePath), ".trial-"+id))
+	}
+	if dir, err := os.UserConfigDir(); err == nil && dir != "" {
+		paths = append(paths, filepath.Join(dir, "ilicense", "trial-"+id+".dat"))
+	}
+	return paths
+}
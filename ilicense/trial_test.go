@@ -0,0 +1,166 @@
+package ilicense
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func trialConfig(t *testing.T) Config {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.PublicKey = "test-key"
+	cfg.StoragePath = filepath.Join(dir, "license.dat")
+	cfg.AllowStartWhenExpired = false
+	cfg.Trial = TrialConfig{
+		Enabled:    true,
+		Days:       14,
+		Modules:    []string{"m-a"},
+		StatePaths: []string{filepath.Join(dir, "a", ".trial"), filepath.Join(dir, "b", ".trial")},
+		Secret:     "trial-secret",
+	}
+	return cfg
+}
+
+func TestInitStartsTrial(t *testing.T) {
+	cfg := trialConfig(t)
+	client := NewClient(&cfg)
+	if err := client.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+
+	status, err := client.CheckLicenseStatus()
+	if status != LicenseStatusTrial || err != nil {
+		t.Fatalf("expected trial status, got %q %v", status, err)
+	}
+	if err := client.CheckModule("m-a"); err != nil {
+		t.Fatalf("expected trial module to be granted, got %v", err)
+	}
+	if client.HasModule("m-b") {
+		t.Fatalf("did not expect non-trial module")
+	}
+	for _, path := range cfg.Trial.StatePaths {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected trial record at %s: %v", path, err)
+		}
+	}
+}
+
+func TestTrialSurvivesDeletingOneRecord(t *testing.T) {
+	cfg := trialConfig(t)
+	first := NewClient(&cfg)
+	if err := first.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+	start := first.GetCurrentLicense().IssueAt
+
+	// Backdate the remaining record so a restarted trial would be detectable.
+	earlier := start.Add(-20 * 24 * time.Hour)
+	if err := first.writeTrialRecord(cfg.Trial.StatePaths[1], earlier); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := os.Remove(cfg.Trial.StatePaths[0]); err != nil {
+		t.Fatalf("unexpected remove error: %v", err)
+	}
+
+	second := NewClient(&cfg)
+	if err := second.Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+	if status, err := second.CheckLicenseStatus(); status != LicenseStatusExpired || !errors.Is(err, ErrLicenseExpired) {
+		t.Fatalf("expected expired trial, got %q %v", status, err)
+	}
+	if got := second.GetCurrentLicense().IssueAt; !got.Equal(earlier) {
+		t.Fatalf("expected trial start %s, got %s", earlier, got)
+	}
+	if _, err := os.Stat(cfg.Trial.StatePaths[0]); err != nil {
+		t.Fatalf("expected deleted trial record to be restored: %v", err)
+	}
+}
+
+func TestTrialTamperedRecord(t *testing.T) {
+	cfg := trialConfig(t)
+	if err := NewClient(&cfg).Init(); err != nil {
+		t.Fatalf("unexpected init error: %v", err)
+	}
+	if err := os.WriteFile(cfg.Trial.StatePaths[0], []byte(`{"started_at":"2099-01-01T00:00:00Z","mac":"00"}`), 0o600); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	client := NewClient(&cfg)
+	if err := client.Init(); !errors.Is(err, ErrTrialTampered) {
+		t.Fatalf("expected ErrTrialTampered, got %v", err)
+	}
+	if client.IsValid() {
+		t.Fatalf("did not expect a license after tampering")
+	}
+}
+
+func TestTrialRejectsFutureStart(t *testing.T) {
+	cfg := trialConfig(t)
+	forger := NewClient(&cfg)
+	future := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, path := range cfg.Trial.StatePaths {
+		if err := forger.writeTrialRecord(path, future); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+
+	client := NewClient(&cfg)
+	if err := client.Init(); !errors.Is(err, ErrTrialTampered) {
+		t.Fatalf("expected ErrTrialTampered for a future start, got %v", err)
+	}
+	if client.IsValid() {
+		t.Fatalf("did not expect a trial from a future start")
+	}
+}
+
+func TestTrialRequiresSecret(t *testing.T) {
+	cfg := trialConfig(t)
+	cfg.Trial.Secret = ""
+	client := NewClient(&cfg)
+	err := client.Init()
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "trial.secret" {
+		t.Fatalf("expected trial.secret ConfigError, got %v", err)
+	}
+	if client.IsValid() {
+		t.Fatalf("did not expect a trial without a secret")
+	}
+	for _, path := range cfg.Trial.StatePaths {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected no trial record without a secret, got %v", err)
+		}
+	}
+}
+
+func TestTrialDefaultPathsArePerProduct(t *testing.T) {
+	shared := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(shared, "config"))
+	t.Setenv("HOME", shared)
+
+	for _, p := range []struct{ key, product, secret string }{
+		{"key-a", "product-a", "secret-a"},
+		{"key-b", "product-b", "secret-b"},
+	} {
+		cfg := trialConfig(t)
+		cfg.PublicKey, cfg.ProductCode = p.key, p.product
+		cfg.StoragePath = filepath.Join(shared, "license.dat")
+		cfg.Trial.StatePaths = nil
+		cfg.Trial.Secret = p.secret
+		client := NewClient(&cfg)
+		if err := client.Init(); err != nil {
+			t.Fatalf("%s: unexpected init error: %v", p.product, err)
+		}
+		if status, err := client.CheckLicenseStatus(); status != LicenseStatusTrial || err != nil {
+			t.Fatalf("%s: expected own trial, got %q %v", p.product, status, err)
+		}
+	}
+	records, _ := filepath.Glob(filepath.Join(shared, "config", "ilicense", "trial-*.dat"))
+	if len(records) != 2 {
+		t.Fatalf("expected one config-dir record per product, got %v", records)
+	}
+}
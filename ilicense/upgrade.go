@@ -0,0 +1,231 @@
+package ilicense
+
+import (
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ActivateOptions tunes ActivateWithOptions.
+type ActivateOptions struct {
+	// Force applies the code even when it would downgrade the stored license.
+	Force bool
+}
+
+// ActivationPreview describes the effect an activation code would have.
+type ActivationPreview struct {
+	// License is the license decoded from the previewed code.
+	License *License `json:"license"`
+	// Replaces is the stored license the code would replace, if any.
+	Replaces *License `json:"replaces,omitempty"`
+	// Downgrade reports that Activate would refuse the code unless forced.
+	Downgrade bool `json:"downgrade"`
+	// Diff compares the effective entitlement before and after activation.
+	Diff LicenseDiff `json:"diff"`
+}
+
+// LicenseDiff lists changes between two effective licenses.
+type LicenseDiff struct {
+	ModulesAdded       []string               `json:"modules_added,omitempty"`
+	ModulesRemoved     []string               `json:"modules_removed,omitempty"`
+	ExpireAtBefore     time.Time              `json:"expire_at_before"`
+	ExpireAtAfter      time.Time              `json:"expire_at_after"`
+	MaxInstancesBefore int                    `json:"max_instances_before"`
+	MaxInstancesAfter  int                    `json:"max_instances_after"`
+	LimitsChanged      map[string]LimitChange `json:"limits_changed,omitempty"`
+}
+
+// LimitChange holds the before and after value of a named limit.
+type LimitChange struct {
+	Before int64 `json:"before"`
+	After  int64 `json:"after"`
+}
+
+// ExpiryChanged reports whether the effective expiry moves.
+func (d LicenseDiff) ExpiryChanged() bool {
+	return !d.ExpireAtBefore.Equal(d.ExpireAtAfter)
+}
+
+// ActivateWithOptions is Activate with explicit options.
+func (m *Client) ActivateWithOptions(activationCode string, opts ActivateOptions) (*License, error) {
+	return m.ActivateWithOptionsContext(context.Background(), activationCode, opts)
+}
+
+// ActivateWithOptionsContext is ActivateWithOptions honoring ctx cancellation
+// and deadlines in storage and hooks.
+func (m *Client) ActivateWithOptionsContext(ctx context.Context, activationCode string, opts ActivateOptions) (*License, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ctx, end := m.startSpan(ctx, SpanActivate)
+	license, err := m.activate(ctx, activationCode, opts)
+	end(err)
+	m.auditActivation(license, err)
+	m.hookActivate(license, err)
+	return license, err
+}
+
+func (m *Client) activate(ctx context.Context, activationCode string, opts ActivateOptions) (*License, error) {
+	m.log.Debug("starting license activation")
+	m.activateMu.Lock()
+	defer m.activateMu.Unlock()
+
+	plan, err := m.planActivation(activationCode)
+	if err != nil {
+		m.log.Warn("license activation failed", errorAttrs(err))
+		m.hookValidationFailure(err)
+		return nil, err
+	}
+	if plan.preview.Downgrade && !opts.Force {
+		err := &DowngradeError{Current: plan.preview.Replaces, Candidate: plan.preview.License}
+		m.log.Warn("license activation refused", licenseAttrs(plan.license), errorAttrs(err))
+		return nil, err
+	}
+	content := joinCodes(plan.entries)
+	if err := m.saveLicenseToFile(ctx, content); err != nil {
+		return nil, err
+	}
+	m.stored.Store(&storedSet{sum: sha256.Sum256([]byte(content)), entries: plan.entries})
+
+	m.setEntries(plan.entries)
+	m.log.Info("license activated", licenseAttrs(plan.license), slog.String("customer_name", plan.license.CustomerName))
+	return plan.license.clone(), nil
+}
+
+// PreviewActivation validates an activation code and reports what Activate
+// would change, without persisting anything. Downgrades are reported through
+// ActivationPreview.Downgrade rather than as an error.
+func (m *Client) PreviewActivation(activationCode string) (*ActivationPreview, error) {
+	plan, err := m.planActivation(activationCode)
+	if err != nil {
+		return nil, err
+	}
+	return plan.preview, nil
+}
+
+type activationPlan struct {
+	license *License
+	entries []storedLicense
+	preview *ActivationPreview
+}
+
+func (m *Client) planActivation(activationCode string) (*activationPlan, error) {
+	license, err := m.verifyCode(activationCode)
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	if license.IsExpired(now) {
+		return nil, ErrLicenseExpired
+	}
+
+	current := m.getEntries()
+	if license.AddOn && !slices.ContainsFunc(current, func(e storedLicense) bool { return !e.license.AddOn }) {
+		return nil, &AddOnWithoutBaseError{LicenseCode: license.LicenseCode}
+	}
+	entries := withLicense(current, storedLicense{code: normalizeCode(activationCode), license: license})
+	replaced := replacedLicense(current, license)
+
+	after := mergeLicenses(entryLicenses(entries), now)
+	preview := &ActivationPreview{
+		License:   license.clone(),
+		Downgrade: replaced != nil && isDowngrade(replaced, license),
+		Diff:      diffLicenses(m.getCurrentLicense(), after),
+	}
+	if replaced != nil {
+		preview.Replaces = replaced.clone()
+	}
+	return &activationPlan{license: license, entries: entries, preview: preview}, nil
+}
+
+// replacedLicense returns the stored license that next would replace.
+func replacedLicense(entries []storedLicense, next *License) *License {
+	for _, e := range entries {
+		if e.license.LicenseCode == next.LicenseCode {
+			return e.license
+		}
+	}
+	if next.AddOn {
+		return nil
+	}
+	for _, e := range entries {
+		if !e.license.AddOn {
+			return e.license
+		}
+	}
+	return nil
+}
+
+// isDowngrade reports whether next is older than the license it replaces:
+// a lower Revision of the same license, or an earlier issued replacement.
+func isDowngrade(current, next *License) bool {
+	if current.LicenseCode == next.LicenseCode {
+		return next.Revision < current.Revision
+	}
+	return next.IssueAt.Before(current.IssueAt)
+}
+
+func diffLicenses(before, after *License) LicenseDiff {
+	var d LicenseDiff
+	beforeModules, afterModules := map[string]bool{}, map[string]bool{}
+	var beforeLimits, afterLimits map[string]int64
+	if before != nil {
+		for _, m := range licenseModules(before) {
+			beforeModules[m] = true
+		}
+		d.ExpireAtBefore = before.ExpireAt
+		d.MaxInstancesBefore = before.MaxInstances
+		beforeLimits = before.Limits
+	}
+	if after != nil {
+		for _, m := range licenseModules(after) {
+			afterModules[m] = true
+		}
+		d.ExpireAtAfter = after.ExpireAt
+		d.MaxInstancesAfter = after.MaxInstances
+		afterLimits = after.Limits
+	}
+	for m := range afterModules {
+		if !beforeModules[m] {
+			d.ModulesAdded = append(d.ModulesAdded, m)
+		}
+	}
+	for m := range beforeModules {
+		if !afterModules[m] {
+			d.ModulesRemoved = append(d.ModulesRemoved, m)
+		}
+	}
+	sort.Strings(d.ModulesAdded)
+	sort.Strings(d.ModulesRemoved)
+	for name := range mergeKeys(beforeLimits, afterLimits) {
+		if b, a := beforeLimits[name], afterLimits[name]; b != a {
+			if d.LimitsChanged == nil {
+				d.LimitsChanged = make(map[string]LimitChange)
+			}
+			d.LimitsChanged[name] = LimitChange{Before: b, After: a}
+		}
+	}
+	return d
+}
+
+func mergeKeys(a, b map[string]int64) map[string]struct{} {
+	out := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		out[k] = struct{}{}
+	}
+	for k := range b {
+		out[k] = struct{}{}
+	}
+	return out
+}
+
+func entryLicenses(entries []storedLicense) []*License {
+	licenses := make([]*License, len(entries))
+	for i, e := range entries {
+		licenses[i] = e.license
+	}
+	return licenses
+}
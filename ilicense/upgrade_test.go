@@ -0,0 +1,69 @@
+package ilicense
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsDowngrade(t *testing.T) {
+	now := time.Now()
+	current := &License{LicenseCode: "L1", Revision: 3, IssueAt: now}
+
+	if !isDowngrade(current, &License{LicenseCode: "L1", Revision: 2, IssueAt: now.Add(time.Hour)}) {
+		t.Fatalf("expected lower revision to be a downgrade")
+	}
+	if isDowngrade(current, &License{LicenseCode: "L1", Revision: 3}) {
+		t.Fatalf("did not expect re-activation of the same revision to be a downgrade")
+	}
+	if !isDowngrade(current, &License{LicenseCode: "L0", IssueAt: now.Add(-time.Hour)}) {
+		t.Fatalf("expected earlier issued replacement to be a downgrade")
+	}
+	if isDowngrade(current, &License{LicenseCode: "L2", IssueAt: now.Add(time.Hour)}) {
+		t.Fatalf("did not expect newer replacement to be a downgrade")
+	}
+}
+
+func TestReplacedLicense(t *testing.T) {
+	base := storedLicense{license: &License{LicenseCode: "base"}}
+	addOn := storedLicense{license: &License{LicenseCode: "add", AddOn: true}}
+	entries := []storedLicense{base, addOn}
+
+	if got := replacedLicense(entries, &License{LicenseCode: "other"}); got != base.license {
+		t.Fatalf("expected new base license to replace base, got %+v", got)
+	}
+	if got := replacedLicense(entries, &License{LicenseCode: "add", AddOn: true}); got != addOn.license {
+		t.Fatalf("expected add-on renewal to replace add-on, got %+v", got)
+	}
+	if got := replacedLicense(entries, &License{LicenseCode: "add-2", AddOn: true}); got != nil {
+		t.Fatalf("did not expect new add-on to replace anything, got %+v", got)
+	}
+}
+
+func TestDiffLicenses(t *testing.T) {
+	before := &License{Modules: "m-a,m-b", ExpireAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), MaxInstances: 2, Limits: map[string]int64{"seats": 5, "sites": 1}}
+	after := &License{Modules: "m-b,m-c", ExpireAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), MaxInstances: 4, Limits: map[string]int64{"seats": 10, "sites": 1}}
+
+	d := diffLicenses(before, after)
+	if !reflect.DeepEqual(d.ModulesAdded, []string{"m-c"}) || !reflect.DeepEqual(d.ModulesRemoved, []string{"m-a"}) {
+		t.Fatalf("unexpected module diff: %+v", d)
+	}
+	if !d.ExpiryChanged() || d.MaxInstancesBefore != 2 || d.MaxInstancesAfter != 4 {
+		t.Fatalf("unexpected expiry or instance diff: %+v", d)
+	}
+	if !reflect.DeepEqual(d.LimitsChanged, map[string]LimitChange{"seats": {Before: 5, After: 10}}) {
+		t.Fatalf("unexpected limit diff: %+v", d.LimitsChanged)
+	}
+
+	if d := diffLicenses(nil, after); len(d.ModulesAdded) != 2 || len(d.ModulesRemoved) != 0 {
+		t.Fatalf("unexpected diff from no license: %+v", d)
+	}
+}
+
+func TestDowngradeErrorUnwrap(t *testing.T) {
+	err := error(&DowngradeError{Current: &License{LicenseCode: "L1", Revision: 2}, Candidate: &License{LicenseCode: "L1", Revision: 1}})
+	if !errors.Is(err, ErrLicenseDowngrade) {
+		t.Fatalf("expected ErrLicenseDowngrade, got %v", err)
+	}
+}
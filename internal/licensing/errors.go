@@ -0,0 +1,58 @@
+package licensing
+
+import "errors"
+
+var (
+	// ErrMalformedEncoding means the activation code is empty, not base64url,
+	// or carries bytes after the signature.
+	ErrMalformedEncoding = errors.New("malformed activation code encoding")
+	// ErrTruncatedPayload means the decoded code is shorter than its length prefixes claim.
+	ErrTruncatedPayload = errors.New("truncated activation payload")
+	// ErrInvalidPublicKey means the configured public key cannot be parsed.
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	// ErrUnsupportedAlgorithm means the public key is not an RSA key.
+	ErrUnsupportedAlgorithm = errors.New("unsupported key algorithm")
+	// ErrInvalidPayload means the signed payload is not valid license JSON.
+	ErrInvalidPayload = errors.New("invalid license payload")
+	// ErrSignatureInvalid means the signature does not match the payload.
+	ErrSignatureInvalid = errors.New("signature verification failed")
+)
+
+// ValidationStage names the step of Validate that failed.
+type ValidationStage string
+
+const (
+	StageDecode    ValidationStage = "decode"
+	StageUnpack    ValidationStage = "unpack"
+	StageKey       ValidationStage = "key"
+	StageSignature ValidationStage = "signature"
+	StageParse     ValidationStage = "parse"
+)
+
+// ValidationError reports why an activation code failed validation.
+// Reason is one of the sentinel errors above; Err is the underlying cause, if any.
+type ValidationError struct {
+	Stage  ValidationStage
+	Reason error
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	msg := "license validation failed: " + e.Reason.Error()
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns Reason and Err so errors.Is matches both.
+func (e *ValidationError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Reason}
+	}
+	return []error{e.Reason, e.Err}
+}
+
+func validationError(stage ValidationStage, reason, err error) error {
+	return &ValidationError{Stage: stage, Reason: reason, Err: err}
+}
@@ -0,0 +1,75 @@
+package licensing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestValidateErrorTaxonomy(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected key error: %v", err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKey := base64.StdEncoding.EncodeToString(der)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+
+	valid, err := Sign(key, &License{LicenseCode: "L1"})
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+	badJSON := pack([]byte("{not json"), mustSign(t, key, []byte("{not json")))
+
+	raw, err := decodeBase64URL(valid)
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	trailing := base64.RawURLEncoding.EncodeToString(append(raw, 0xde, 0xad, 0xbe, 0xef))
+
+	truncated := make([]byte, 8)
+	binary.BigEndian.PutUint32(truncated, 100)
+
+	cases := []struct {
+		name      string
+		publicKey string
+		code      string
+		stage     ValidationStage
+		reason    error
+	}{
+		{"empty", publicKey, "", StageDecode, ErrMalformedEncoding},
+		{"not base64", publicKey, "!!!", StageDecode, ErrMalformedEncoding},
+		{"truncated", publicKey, base64.RawURLEncoding.EncodeToString(truncated), StageUnpack, ErrTruncatedPayload},
+		{"trailing bytes", publicKey, trailing, StageUnpack, ErrMalformedEncoding},
+		{"bad key", "not-a-key", valid, StageKey, ErrInvalidPublicKey},
+		{"ecdsa key", base64.StdEncoding.EncodeToString(ecDER), valid, StageKey, ErrUnsupportedAlgorithm},
+		{"bad json", publicKey, badJSON, StageParse, ErrInvalidPayload},
+		{"signature", publicKey, valid[:len(valid)-4] + "AAAA", StageSignature, ErrSignatureInvalid},
+	}
+	for _, tc := range cases {
+		_, err := Validate(tc.publicKey, tc.code)
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Stage != tc.stage || !errors.Is(err, tc.reason) {
+			t.Fatalf("%s: expected %s/%v, got %v", tc.name, tc.stage, tc.reason, err)
+		}
+	}
+}
+
+func mustSign(t *testing.T, key *rsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+	hash := sha256.Sum256(data)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+	return sig
+}
@@ -19,10 +19,26 @@ type License struct {
 	Modules      string    `json:"modules"`
 	MaxInstances int       `json:"max_instances"`
 
+	MaintenanceExpireAt time.Time        `json:"maintenance_expire_at"`
+	ProductVersions     string           `json:"product_versions,omitempty"`
+	Revision            int              `json:"revision,omitempty"`
+	AddOn               bool             `json:"add_on,omitempty"`
+	Limits              map[string]int64 `json:"limits,omitempty"`
+
+	Claims   map[string]any     `json:"claims,omitempty"`
+	Edition  string             `json:"edition,omitempty"`
+	Editions map[string]Edition `json:"editions,omitempty"`
+
 	Valid    bool  `json:"valid"`
 	DaysLeft int64 `json:"days_left"`
 }
 
+// Edition mirrors a signed edition catalogue entry.
+type Edition struct {
+	Modules  []string `json:"modules"`
+	Includes []string `json:"includes,omitempty"`
+}
+
 func (l License) IsExpired(now time.Time) bool {
 	if l.ExpireAt.IsZero() {
 		return false
@@ -1,6 +1,10 @@
 package licensing
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
 
 func TestHasModuleExactMatch(t *testing.T) {
 	l := License{Modules: "m-a,m-aa,m-b"}
@@ -26,3 +30,31 @@ func TestHasModuleTrimmedMatch(t *testing.T) {
 		t.Fatalf("did not expect empty module to be found")
 	}
 }
+
+func TestParseLicenseDataClaims(t *testing.T) {
+	l, err := parseLicenseData([]byte(`{"license_code":"L1","claims":{"region":"eu","seats":12}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l.Claims["region"] != "eu" {
+		t.Fatalf("expected region claim, got %v", l.Claims["region"])
+	}
+	if _, ok := l.Claims["seats"].(json.Number); !ok {
+		t.Fatalf("expected numeric claim to be json.Number, got %T", l.Claims["seats"])
+	}
+}
+
+func TestParseLicenseDataRejectsTrailingData(t *testing.T) {
+	for _, data := range []string{
+		`{"license_code":"L1"}{"license_code":"L2"}`,
+		`{"license_code":"L1"} x`,
+		`{"license_code":"L1"}}`,
+	} {
+		if _, err := parseLicenseData([]byte(data)); !errors.Is(err, ErrInvalidPayload) {
+			t.Fatalf("parseLicenseData(%s): expected ErrInvalidPayload, got %v", data, err)
+		}
+	}
+	if _, err := parseLicenseData([]byte("{\"license_code\":\"L1\"}\n")); err != nil {
+		t.Fatalf("expected trailing whitespace to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package licensing
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+// Sign serializes license and signs it with RSA PKCS#1 v1.5 over SHA-256,
+// producing an activation code in the format accepted by Validate.
+func Sign(privateKey *rsa.PrivateKey, license *License) (string, error) {
+	if privateKey == nil {
+		return "", errors.New("private key is nil")
+	}
+	if license == nil {
+		return "", errors.New("license is nil")
+	}
+	data, err := json.Marshal(license)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return pack(data, signature), nil
+}
+
+// pack encodes payload and signature as length-prefixed, URL-safe base64 binary.
+func pack(data, signature []byte) string {
+	buf := make([]byte, 0, 8+len(data)+len(signature))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+	buf = append(buf, data...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(signature)))
+	buf = append(buf, signature...)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
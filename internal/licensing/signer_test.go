@@ -0,0 +1,63 @@
+package licensing
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignValidateRoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected key error: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	publicKey := base64.StdEncoding.EncodeToString(der)
+
+	in := &License{LicenseCode: "L1", Modules: "m-a", ExpireAt: time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)}
+	code, err := Sign(key, in)
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+
+	out, err := Validate(publicKey, code)
+	if err != nil {
+		t.Fatalf("unexpected validate error: %v", err)
+	}
+	if out.LicenseCode != "L1" || !out.ExpireAt.Equal(in.ExpireAt) || !out.Valid || out.DaysLeft != 1 {
+		t.Fatalf("unexpected license: %+v", out)
+	}
+
+	parsed, err := ParsePublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if out, err := Verify(parsed, code); err != nil || out.LicenseCode != "L1" {
+		t.Fatalf("expected Verify to match Validate, got %+v %v", out, err)
+	}
+
+	tampered := []byte(code)
+	tampered[len(tampered)/3] ^= 1
+	if _, err := Validate(publicKey, string(tampered)); err == nil {
+		t.Fatalf("expected tampered code to fail")
+	}
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected key error: %v", err)
+	}
+	otherCode, err := Sign(other, in)
+	if err != nil {
+		t.Fatalf("unexpected sign error: %v", err)
+	}
+	if _, err := Validate(publicKey, otherCode); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected ErrSignatureInvalid, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package licensing
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -10,14 +11,68 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"errors"
-	"fmt"
+	"io"
 	"strings"
 	"time"
 )
 
-var ErrSignatureInvalid = errors.New("signature verification failed")
-
 func Validate(publicKey string, activationCode string) (*License, error) {
+	dataBytes, signatureBytes, err := unpack(activationCode)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := loadPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return verify(dataBytes, signatureBytes, pubKey)
+}
+
+// ParsePublicKey parses a PEM or base64 PKIX RSA public key so it can be
+// reused across calls to Verify.
+func ParsePublicKey(publicKey string) (*rsa.PublicKey, error) {
+	return loadPublicKey(publicKey)
+}
+
+// Verify is Validate with a key parsed by ParsePublicKey.
+func Verify(publicKey *rsa.PublicKey, activationCode string) (*License, error) {
+	dataBytes, signatureBytes, err := unpack(activationCode)
+	if err != nil {
+		return nil, err
+	}
+	return verify(dataBytes, signatureBytes, publicKey)
+}
+
+func verify(dataBytes, signatureBytes []byte, pubKey *rsa.PublicKey) (*License, error) {
+	if err := verifySignature(dataBytes, signatureBytes, pubKey); err != nil {
+		return nil, err
+	}
+	info, err := parseLicenseData(dataBytes)
+	if err != nil {
+		return nil, err
+	}
+	fillDerived(info)
+	return info, nil
+}
+
+// Decode parses the license payload of an activation code WITHOUT verifying
+// its signature. It is meant for diagnostics only.
+func Decode(activationCode string) (*License, error) {
+	dataBytes, _, err := unpack(activationCode)
+	if err != nil {
+		return nil, err
+	}
+	info, err := parseLicenseData(dataBytes)
+	if err != nil {
+		return nil, err
+	}
+	fillDerived(info)
+	return info, nil
+}
+
+// unpack splits an activation code into its payload and signature.
+func unpack(activationCode string) ([]byte, []byte, error) {
 	cleaned := strings.TrimSpace(strings.Map(func(r rune) rune {
 		switch r {
 		case ' ', '\n', '\r', '\t':
@@ -29,46 +84,37 @@ func Validate(publicKey string, activationCode string) (*License, error) {
 
 	decoded, err := decodeBase64URL(cleaned)
 	if err != nil {
-		return nil, fmt.Errorf("license validation failed: %w", err)
+		return nil, nil, validationError(StageDecode, ErrMalformedEncoding, err)
 	}
 
 	if len(decoded) < 8 {
-		return nil, errors.New("license validation failed: activation payload too short")
+		return nil, nil, validationError(StageUnpack, ErrTruncatedPayload, errors.New("activation payload too short"))
 	}
 
 	dataLen := int(binary.BigEndian.Uint32(decoded[:4]))
 	if dataLen < 0 || 4+dataLen+4 > len(decoded) {
-		return nil, errors.New("license validation failed: invalid data length")
+		return nil, nil, validationError(StageUnpack, ErrTruncatedPayload, errors.New("invalid data length"))
 	}
 	dataBytes := decoded[4 : 4+dataLen]
 
 	sigLenOffset := 4 + dataLen
 	sigLen := int(binary.BigEndian.Uint32(decoded[sigLenOffset : sigLenOffset+4]))
 	if sigLen < 0 || sigLenOffset+4+sigLen > len(decoded) {
-		return nil, errors.New("license validation failed: invalid signature length")
+		return nil, nil, validationError(StageUnpack, ErrTruncatedPayload, errors.New("invalid signature length"))
 	}
-	signatureBytes := decoded[sigLenOffset+4 : sigLenOffset+4+sigLen]
-
-	pubKey, err := loadPublicKey(publicKey)
-	if err != nil {
-		return nil, fmt.Errorf("license validation failed: %w", err)
-	}
-
-	if err := verifySignature(dataBytes, signatureBytes, pubKey); err != nil {
-		return nil, err
-	}
-	info, err := parseLicenseData(dataBytes)
-	if err != nil {
-		return nil, fmt.Errorf("license validation failed: %w", err)
+	// Trailing bytes would give one license unlimited distinct codes.
+	if sigLenOffset+4+sigLen != len(decoded) {
+		return nil, nil, validationError(StageUnpack, ErrMalformedEncoding, errors.New("unexpected data after signature"))
 	}
+	return dataBytes, decoded[sigLenOffset+4 : sigLenOffset+4+sigLen], nil
+}
 
+func fillDerived(info *License) {
 	now := time.Now()
 	info.Valid = !info.IsExpired(now)
 	if !info.ExpireAt.IsZero() {
 		info.DaysLeft = int64(info.ExpireAt.Sub(now).Hours() / 24)
 	}
-
-	return info, nil
 }
 
 func decodeBase64URL(s string) ([]byte, error) {
@@ -83,13 +129,31 @@ func decodeBase64URL(s string) ([]byte, error) {
 
 func parseLicenseData(data []byte) (*License, error) {
 	var license License
-	if err := json.Unmarshal(data, &license); err != nil {
-		return nil, err
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Keep numeric claims as json.Number so integer claims do not lose precision.
+	dec.UseNumber()
+	if err := dec.Decode(&license); err != nil {
+		return nil, validationError(StageParse, ErrInvalidPayload, err)
+	}
+	// Reject trailing data as json.Unmarshal does.
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, validationError(StageParse, ErrInvalidPayload, errors.New("unexpected data after license payload"))
 	}
 	return &license, nil
 }
 
 func loadPublicKey(publicKeyStr string) (*rsa.PublicKey, error) {
+	pub, err := parsePublicKey(publicKeyStr)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedAlgorithm) {
+			return nil, validationError(StageKey, ErrUnsupportedAlgorithm, nil)
+		}
+		return nil, validationError(StageKey, ErrInvalidPublicKey, err)
+	}
+	return pub, nil
+}
+
+func parsePublicKey(publicKeyStr string) (*rsa.PublicKey, error) {
 	if strings.TrimSpace(publicKeyStr) == "" {
 		return nil, errors.New("public key is empty")
 	}
@@ -106,7 +170,7 @@ func loadPublicKey(publicKeyStr string) (*rsa.PublicKey, error) {
 		}
 		pub, ok := key.(*rsa.PublicKey)
 		if !ok {
-			return nil, errors.New("public key is not RSA")
+			return nil, ErrUnsupportedAlgorithm
 		}
 		return pub, nil
 	}
@@ -126,7 +190,7 @@ func loadPublicKey(publicKeyStr string) (*rsa.PublicKey, error) {
 	}
 	pub, ok := key.(*rsa.PublicKey)
 	if !ok {
-		return nil, errors.New("public key is not RSA")
+		return nil, ErrUnsupportedAlgorithm
 	}
 	return pub, nil
 }
@@ -134,7 +198,7 @@ func loadPublicKey(publicKeyStr string) (*rsa.PublicKey, error) {
 func verifySignature(data, signature []byte, publicKey *rsa.PublicKey) error {
 	hash := sha256.Sum256(data)
 	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature); err != nil {
-		return ErrSignatureInvalid
+		return validationError(StageSignature, ErrSignatureInvalid, err)
 	}
 	return nil
 }
@@ -0,0 +1,243 @@
+// Package semver implements the subset of semantic versioning needed to match
+// product versions against signed license constraints.
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+}
+
+// Parse parses versions such as "1.2.3", "v1.2" or "2.0.0-rc.1". Build metadata is ignored.
+func Parse(s string) (Version, error) {
+	var v Version
+	input := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return v, errors.New("empty version")
+	}
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Pre = s[i+1:]
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", input)
+	}
+	nums := [3]int{}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", input)
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than o.
+func (v Version) Compare(o Version) int {
+	for _, d := range [3]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return comparePre(v.Pre, o.Pre)
+}
+
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	ap, bp := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, aErr := strconv.Atoi(ap[i])
+		bn, bErr := strconv.Atoi(bp[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(ap[i], bp[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(ap) - len(bp))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Constraint is a set of version ranges joined by "||"; comparators within a
+// range are joined by spaces or commas and must all match.
+type Constraint struct {
+	ranges [][]comparator
+}
+
+type comparator struct {
+	op string
+	v  Version
+}
+
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "=", "^", "~"}
+
+// ParseConstraint parses constraints such as ">=1.2.0 <2.0.0", "^1.4 || ~2.1.0" or "*".
+// An operator may be separated from its version by spaces, as in ">= 1.2.0".
+// Empty ranges, such as a trailing "||", are rejected.
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	for _, group := range strings.Split(s, "||") {
+		terms, err := splitTerms(group)
+		if err != nil {
+			return c, fmt.Errorf("invalid constraint %q: %w", s, err)
+		}
+		var rng []comparator
+		for _, term := range terms {
+			if term == "*" || term == "x" {
+				continue
+			}
+			op := "="
+			for _, candidate := range operators {
+				if strings.HasPrefix(term, candidate) {
+					op, term = candidate, strings.TrimPrefix(term, candidate)
+					break
+				}
+			}
+			if op == "==" {
+				op = "="
+			}
+			v, err := Parse(term)
+			if err != nil {
+				return c, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			rng = append(rng, expand(op, v, term)...)
+		}
+		c.ranges = append(c.ranges, rng)
+	}
+	return c, nil
+}
+
+// splitTerms splits a range into comparator terms, joining a bare operator
+// with the version that follows it.
+func splitTerms(group string) ([]string, error) {
+	fields := strings.FieldsFunc(group, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, errors.New("empty range")
+	}
+	terms := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+		if slices.Contains(operators, term) {
+			if i+1 == len(fields) {
+				return nil, fmt.Errorf("operator %q without version", term)
+			}
+			i++
+			term += fields[i]
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// expand rewrites caret and tilde ranges into plain comparators.
+func expand(op string, v Version, raw string) []comparator {
+	// dots counts the version components given: "1" has 0, "1.2.3" has 2.
+	dots := strings.Count(strings.TrimPrefix(raw, "v"), ".")
+	switch op {
+	case "^":
+		// Caret allows changes that keep the left-most non-zero component given.
+		upper := Version{Major: v.Major + 1}
+		switch {
+		case v.Major == 0 && v.Minor == 0 && dots >= 2:
+			upper = Version{Patch: v.Patch + 1}
+		case v.Major == 0 && dots >= 1:
+			upper = Version{Minor: v.Minor + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}
+	case "~":
+		upper := Version{Major: v.Major, Minor: v.Minor + 1}
+		if dots == 0 {
+			upper = Version{Major: v.Major + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}
+	default:
+		return []comparator{{op, v}}
+	}
+}
+
+// Check reports whether v satisfies the constraint. As in other semver
+// implementations, a prerelease only satisfies a range that has a comparator
+// with a prerelease of the same major.minor.patch, so "<2.0.0" and "^1.4"
+// do not admit 2.0.0-rc.1.
+func (c Constraint) Check(v Version) bool {
+	for _, rng := range c.ranges {
+		if matchAll(rng, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAll(rng []comparator, v Version) bool {
+	if v.Pre != "" && !slices.ContainsFunc(rng, func(cmp comparator) bool {
+		return cmp.v.Pre != "" && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch
+	}) {
+		return false
+	}
+	for _, cmp := range rng {
+		d := v.Compare(cmp.v)
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = d == 0
+		case "!=":
+			ok = d != 0
+		case ">":
+			ok = d > 0
+		case ">=":
+			ok = d >= 0
+		case "<":
+			ok = d < 0
+		case "<=":
+			ok = d <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=1.2.0 <2.0.0", "1.5.3", true},
+		{">=1.2.0 <2.0.0", "2.0.0", false},
+		{">=1.2.0, <2.0.0", "1.1.9", false},
+		{"^1.4", "1.9.0", true},
+		{"^1.4", "2.0.0", false},
+		{"^0.3.1", "0.4.0", false},
+		{"~2.1.0", "2.1.7", true},
+		{"~2.1.0", "2.2.0", false},
+		{"^1.0 || ~3.1", "3.1.2", true},
+		{"*", "9.9.9", true},
+		{"1.2.3", "v1.2.3", true},
+		{"<2.0.0", "2.0.0-rc.1", false},
+		{">=1.4.0 <2.0.0", "2.0.0-rc.1", false},
+		{"^1.4", "2.0.0-rc.1", false},
+		{"^1.4", "1.5.0-beta", false},
+		{"*", "1.0.0-rc.1", false},
+		{">=2.0.0-rc.2", "2.0.0-rc.10", true},
+		{">=2.0.0-rc.2", "2.0.0-rc.1", false},
+		{">=2.0.0-rc.2", "2.1.0-rc.1", false},
+		{">=2.0.0-rc.2", "2.1.0", true},
+		{">=1.0.0 || >=2.0.0-rc.1 <2.0.0", "2.0.0-rc.3", true},
+		{"^0.0.3", "0.0.3", true},
+		{"^0.0.3", "0.0.9", false},
+		{"^0.0", "0.0.9", true},
+		{"^0.0", "0.1.0", false},
+		{"^0", "0.9.0", true},
+		{"^0", "1.0.0", false},
+		{">= 1.0.0", "1.0.0", true},
+		{">= 1.0.0 < 2.0.0", "2.0.0", false},
+		{"^ 1.2 || ~ 3.1", "3.1.4", true},
+	}
+	for _, tc := range cases {
+		c, err := ParseConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("unexpected constraint error for %q: %v", tc.constraint, err)
+		}
+		v, err := Parse(tc.version)
+		if err != nil {
+			t.Fatalf("unexpected version error for %q: %v", tc.version, err)
+		}
+		if got := c.Check(v); got != tc.want {
+			t.Fatalf("%q check %q: expected %v, got %v", tc.constraint, tc.version, tc.want, got)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "1.x.0", "1.2.3.4"} {
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+	for _, s := range []string{">=abc", "", ">=9.0.0 ||", "|| 1.0.0", ">=1.0.0 || || <2.0.0", ">=", "1.0.0 <"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Fatalf("expected constraint error for %q", s)
+		}
+	}
+}
+
+func TestParseErrorReportsInput(t *testing.T) {
+	_, err := Parse("not-a-version")
+	if err == nil || !strings.Contains(err.Error(), `"not-a-version"`) {
+		t.Fatalf("expected error to quote the full input, got %v", err)
+	}
+}